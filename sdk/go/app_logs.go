@@ -0,0 +1,238 @@
+package msb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LogFormat selects how AppLogs parses application log lines.
+type LogFormat int
+
+const (
+	LogFormatJSON   LogFormat = iota + 1 // One JSON object per line
+	LogFormatLogfmt                      // key=value pairs, as produced by logfmt and slog's text handler
+)
+
+// LogRecord is a single structured application log entry.
+// Lines that cannot be parsed in the requested format yield a record with only Message and Raw set.
+type LogRecord struct {
+	Time    time.Time      // Entry timestamp; zero if absent or unparseable
+	Level   string         // Severity as written by the application (e.g. "info", "ERROR")
+	Message string         // Log message
+	Fields  map[string]any // Remaining key/value pairs
+	Raw     string         // Original line
+}
+
+// AppLogs checks the log file for new lines every appLogPollInterval. While tailing keeps failing,
+// the interval doubles up to appLogMaxPollInterval.
+const (
+	appLogPollInterval    = 500 * time.Millisecond
+	appLogMaxPollInterval = 10 * time.Second
+)
+
+// Keys recognised for the well-known record fields, in order of preference
+var (
+	logTimeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+	logLevelKeys   = []string{"level", "lvl", "severity"}
+	logMessageKeys = []string{"msg", "message"}
+)
+
+type appLogReader struct {
+	b *baseMicroSandbox
+}
+
+func (ar appLogReader) AppLogs(ctx context.Context, format LogFormat) (<-chan LogRecord, error) {
+	if ar.b.state.Load() != started {
+		return nil, ErrSandboxNotStarted
+	}
+	if ar.b.cfg.appLogPath == "" {
+		return nil, ErrAppLogNotConfigured
+	}
+	if format != LogFormatJSON && format != LogFormatLogfmt {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLogFormat, format)
+	}
+
+	ch := make(chan LogRecord)
+	go func() {
+		defer close(ch)
+		offset := tailFromEnd
+		interval := appLogPollInterval
+		failing := false
+		for {
+			chunk, err := ar.b.rpcClient.tailFile(ctx, &ar.b.cfg, ar.b.cfg.appLogPath, offset)
+			switch {
+			case err == nil:
+				if failing {
+					ar.b.cfg.logger.Info("Resumed tailing application log", "sandbox", ar.b.cfg.sandboxName(), "path", ar.b.cfg.appLogPath)
+				}
+				failing, interval = false, appLogPollInterval
+				offset = chunk.Offset
+				for _, line := range chunk.Lines {
+					select {
+					case ch <- parseLogLine(line, format):
+					case <-ctx.Done():
+						return
+					}
+				}
+			case ctx.Err() != nil:
+				return
+			default:
+				// Report the start of a failure streak once, then back off quietly
+				if !failing {
+					ar.b.cfg.logger.Error("Failed to tail application log", "sandbox", ar.b.cfg.sandboxName(), "path", ar.b.cfg.appLogPath, "error", err)
+				} else {
+					ar.b.cfg.logger.Debug("Still failing to tail application log", "sandbox", ar.b.cfg.sandboxName(), "path", ar.b.cfg.appLogPath, "error", err)
+				}
+				failing, interval = true, min(interval*2, appLogMaxPollInterval)
+				// A file that could not be read at first (e.g. not created yet) is read from its beginning
+				if offset == tailFromEnd {
+					offset = 0
+				}
+			}
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func parseLogLine(line string, format LogFormat) LogRecord {
+	var fields map[string]any
+	var ok bool
+	switch format {
+	case LogFormatJSON:
+		ok = json.Unmarshal([]byte(line), &fields) == nil && fields != nil
+	case LogFormatLogfmt:
+		fields, ok = parseLogfmt(line)
+	}
+	if !ok {
+		return LogRecord{Message: line, Raw: line}
+	}
+
+	rec := LogRecord{Raw: line}
+	if v, found := takeField(fields, logTimeKeys); found {
+		rec.Time = parseLogTime(v)
+	}
+	if v, found := takeField(fields, logLevelKeys); found {
+		rec.Level = fmt.Sprint(v)
+	}
+	if v, found := takeField(fields, logMessageKeys); found {
+		rec.Message = fmt.Sprint(v)
+	}
+	if len(fields) > 0 {
+		rec.Fields = fields
+	}
+	return rec
+}
+
+// takeField removes and returns the value of the first of keys present in fields.
+func takeField(fields map[string]any, keys []string) (any, bool) {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			delete(fields, k)
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// parseLogTime accepts RFC 3339 strings and numeric Unix timestamps in seconds or, when too large
+// for seconds (as written by e.g. pino), milliseconds. Values out of range yield the zero time.
+func parseLogTime(v any) time.Time {
+	switch t := v.(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return parsed
+		}
+		if secs, err := strconv.ParseFloat(t, 64); err == nil {
+			return unixFloat(secs)
+		}
+	case float64:
+		return unixFloat(t)
+	}
+	return time.Time{}
+}
+
+// maxUnixSeconds is the largest timestamp read as seconds (year 5138); larger ones are milliseconds.
+const maxUnixSeconds = 1e11
+
+func unixFloat(ts float64) time.Time {
+	unit := time.Second
+	if math.Abs(ts) >= maxUnixSeconds {
+		unit = time.Millisecond
+	}
+	// Beyond this, nanoseconds overflow int64
+	if math.IsNaN(ts) || math.Abs(ts) >= math.MaxInt64/float64(unit) {
+		return time.Time{}
+	}
+	// Whole and fractional parts are scaled separately to keep sub-unit precision
+	whole, frac := math.Modf(ts)
+	return time.Unix(0, int64(whole)*int64(unit)+int64(math.Round(frac*float64(unit))))
+}
+
+// parseLogfmt parses a line of space-separated key=value pairs. Values may be double-quoted with
+// Go-style escapes; a bare key is recorded as true. Reports false if the line is not valid logfmt,
+// including when it has no key=value pair at all, so plain prose is not mistaken for bare keys.
+func parseLogfmt(line string) (map[string]any, bool) {
+	fields := map[string]any{}
+	hasPair := false
+	s := strings.TrimSpace(line)
+	for s != "" {
+		end := strings.IndexFunc(s, func(r rune) bool { return r == '=' || unicode.IsSpace(r) })
+		if end < 0 {
+			end = len(s)
+		}
+		key := s[:end]
+		if key == "" || strings.ContainsRune(key, '"') {
+			return nil, false
+		}
+		s = s[end:]
+
+		if !strings.HasPrefix(s, "=") {
+			fields[key] = true
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			continue
+		}
+		s = s[1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			s = s[len(quoted):]
+		} else {
+			end := strings.IndexFunc(s, unicode.IsSpace)
+			if end < 0 {
+				end = len(s)
+			}
+			value = s[:end]
+			s = s[end:]
+		}
+		fields[key] = value
+		hasPair = true
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	}
+	if !hasPair {
+		return nil, false
+	}
+	return fields, true
+}
+
+// Application log errors
+var (
+	ErrAppLogNotConfigured = errors.New("application log file not configured; use WithAppLogFile()")
+	ErrUnknownLogFormat    = errors.New("unknown log format")
+)
@@ -0,0 +1,181 @@
+package msb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLogfmt(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   map[string]any
+		wantOK bool
+	}{
+		{
+			name:   "simple pairs",
+			line:   `level=info msg=started port=8080`,
+			want:   map[string]any{"level": "info", "msg": "started", "port": "8080"},
+			wantOK: true,
+		},
+		{
+			name:   "quoted value with escapes",
+			line:   `msg="hello \"world\"\n" user=bob`,
+			want:   map[string]any{"msg": "hello \"world\"\n", "user": "bob"},
+			wantOK: true,
+		},
+		{
+			name:   "quoted value with spaces",
+			line:   `msg="request done" status=200`,
+			want:   map[string]any{"msg": "request done", "status": "200"},
+			wantOK: true,
+		},
+		{
+			name:   "bare key recorded as true",
+			line:   `debug msg=x`,
+			want:   map[string]any{"debug": true, "msg": "x"},
+			wantOK: true,
+		},
+		{
+			name:   "empty value",
+			line:   `msg= level=warn`,
+			want:   map[string]any{"msg": "", "level": "warn"},
+			wantOK: true,
+		},
+		{
+			name:   "surrounding whitespace",
+			line:   "  a=1\tb=2  ",
+			want:   map[string]any{"a": "1", "b": "2"},
+			wantOK: true,
+		},
+		{
+			name: "plain prose",
+			line: `server started on port 8080`,
+		},
+		{
+			name: "empty line",
+			line: ``,
+		},
+		{
+			name: "unterminated quote",
+			line: `msg="oops level=info`,
+		},
+		{
+			name: "missing key",
+			line: `=value`,
+		},
+		{
+			name: "quote in key",
+			line: `say "hi" msg=x`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLogfmt(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseLogfmt(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLogfmt(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogTime(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want time.Time
+	}{
+		{name: "RFC 3339", v: "2024-05-01T12:30:00Z", want: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)},
+		{name: "RFC 3339 with fraction", v: "2024-05-01T12:30:00.25Z", want: time.Date(2024, 5, 1, 12, 30, 0, 250_000_000, time.UTC)},
+		{name: "numeric string", v: "1714566600", want: time.Unix(1714566600, 0)},
+		{name: "fractional numeric string", v: "1714566600.5", want: time.Unix(1714566600, 500_000_000)},
+		{name: "JSON number", v: float64(1714566600), want: time.Unix(1714566600, 0)},
+		{name: "epoch milliseconds", v: float64(1714566600123), want: time.Unix(1714566600, 123_000_000)},
+		{name: "epoch milliseconds string", v: "1714566600000", want: time.Unix(1714566600, 0)},
+		{name: "out of range", v: float64(1e30)},
+		{name: "NaN string", v: "NaN"},
+		{name: "unparseable string", v: "yesterday"},
+		{name: "unsupported type", v: true},
+		{name: "nil", v: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLogTime(tt.v); !got.Equal(tt.want) {
+				t.Errorf("parseLogTime(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		format LogFormat
+		want   LogRecord
+	}{
+		{
+			name:   "JSON record",
+			line:   `{"time":"2024-05-01T12:30:00Z","level":"INFO","msg":"ready","port":8080}`,
+			format: LogFormatJSON,
+			want: LogRecord{
+				Time:    time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+				Level:   "INFO",
+				Message: "ready",
+				Fields:  map[string]any{"port": float64(8080)},
+			},
+		},
+		{
+			name:   "JSON alternative keys",
+			line:   `{"ts":1714566600,"severity":"error","message":"boom"}`,
+			format: LogFormatJSON,
+			want:   LogRecord{Time: time.Unix(1714566600, 0), Level: "error", Message: "boom"},
+		},
+		{
+			name:   "logfmt record",
+			line:   `time=2024-05-01T12:30:00Z level=warn msg="disk low" free=10%`,
+			format: LogFormatLogfmt,
+			want: LogRecord{
+				Time:    time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+				Level:   "warn",
+				Message: "disk low",
+				Fields:  map[string]any{"free": "10%"},
+			},
+		},
+		{
+			name:   "invalid JSON falls back to raw",
+			line:   `not json`,
+			format: LogFormatJSON,
+			want:   LogRecord{Message: "not json"},
+		},
+		{
+			name:   "JSON non-object falls back to raw",
+			line:   `null`,
+			format: LogFormatJSON,
+			want:   LogRecord{Message: "null"},
+		},
+		{
+			name:   "prose falls back to raw in logfmt",
+			line:   `Listening on :8080`,
+			format: LogFormatLogfmt,
+			want:   LogRecord{Message: "Listening on :8080"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Raw = tt.line
+			got := parseLogLine(tt.line, tt.format)
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLogLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
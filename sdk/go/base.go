@@ -13,7 +13,9 @@ func newBaseWithOptions(options ...Option) *baseMicroSandbox {
 	for _, opt := range append(options,
 		fillDefaultConfigs(),
 		fillDefaultLogger(),
+		fillDefaultCollector(),
 		fillDefaultRPCClient(),
+		fillCertPinning(),
 	) {
 		opt(msb)
 	}
@@ -32,7 +34,22 @@ var (
 	ErrSandboxNotStarted     = errors.New("sandbox not started")
 	ErrFailedToStartSandbox  = errors.New("failed to start sandbox")
 	ErrFailedToStopSandbox   = errors.New("failed to stop sandbox")
+	ErrFakeTimeUnsupported   = errors.New("fake time not supported by image")
 	ErrFailedToRunCode       = errors.New("failed to run code")
 	ErrFailedToRunCommand    = errors.New("failed to run command")
+	ErrEmptyPipeline         = errors.New("pipeline must have at least one stage")
 	ErrFailedToGetMetrics    = errors.New("failed to get metrics")
+	ErrFailedToGetLimits     = errors.New("failed to get limits")
+	ErrFailedToGetKernelInfo = errors.New("failed to get kernel info")
+
+	ErrSecurityProfileUnsupported = errors.New("security profile not supported by server")
+	ErrUnknownCapability          = errors.New("unknown Linux capability")
+
+	ErrFailedToRenameSandbox = errors.New("failed to rename sandbox")
+	ErrInvalidSandboxName    = errors.New("invalid sandbox name")
+	ErrSandboxNameTaken      = errors.New("sandbox name already taken in namespace")
+
+	ErrFailedToListCrashArtifacts    = errors.New("failed to list crash artifacts")
+	ErrFailedToDownloadCrashArtifact = errors.New("failed to download crash artifact")
+	ErrFailedToWriteFile             = errors.New("failed to write file")
 )
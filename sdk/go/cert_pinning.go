@@ -0,0 +1,67 @@
+package msb
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// normalizeFingerprint lowercases a hex SHA-256 fingerprint and strips ":" separators,
+// so both "AB:CD:..." (as printed by openssl) and "abcd..." are accepted.
+func normalizeFingerprint(fingerprint string) (string, error) {
+	fp := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCertFingerprint, fingerprint)
+	}
+	return fp, nil
+}
+
+// pinnedHTTPClient returns a copy of c whose transport additionally rejects any server
+// leaf certificate that does not hash to fingerprint. The caller's client is left untouched.
+func pinnedHTTPClient(c *http.Client, fingerprint string) (*http.Client, error) {
+	var transport *http.Transport
+	switch t := c.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("%w: unsupported transport %T", ErrCertificatePinningUnsupported, c.Transport)
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	// VerifyConnection rather than VerifyPeerCertificate, since the latter is skipped on resumed sessions.
+	// Regular chain verification still runs first; the pin is an additional check.
+	next := transport.TLSClientConfig.VerifyConnection
+	transport.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return ErrCertificatePinMismatch
+		}
+		sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if got := hex.EncodeToString(sum[:]); got != fingerprint {
+			return fmt.Errorf("%w: got %s", ErrCertificatePinMismatch, got)
+		}
+		if next != nil {
+			return next(cs)
+		}
+		return nil
+	}
+
+	pinned := *c
+	pinned.Transport = transport
+	return &pinned, nil
+}
+
+// Certificate pinning errors
+var (
+	ErrCertificatePinMismatch          = errors.New("server certificate does not match pinned fingerprint")
+	ErrInvalidCertFingerprint          = errors.New("invalid certificate fingerprint: expected hex-encoded SHA-256")
+	ErrCertificatePinningUnsupported   = errors.New("certificate pinning requires an *http.Transport")
+	ErrCertificatePinningRequiresHTTPS = errors.New("certificate pinning requires an https server URL")
+)
@@ -0,0 +1,115 @@
+package msb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPinTestServer starts a TLS server answering every JSON-RPC call with an empty sandbox list,
+// and returns it along with the hex SHA-256 fingerprint of its certificate.
+func newPinTestServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"sandboxes":[]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	return srv, hex.EncodeToString(sum[:])
+}
+
+// colonSeparated formats a hex fingerprint as "AB:CD:...", as printed by openssl.
+func colonSeparated(fp string) string {
+	pairs := make([]string, 0, len(fp)/2)
+	for i := 0; i < len(fp); i += 2 {
+		pairs = append(pairs, fp[i:i+2])
+	}
+	return strings.ToUpper(strings.Join(pairs, ":"))
+}
+
+func TestCertificatePinning(t *testing.T) {
+	srv, fp := newPinTestServer(t)
+	other := strings.Repeat("00", sha256.Size)
+
+	tests := []struct {
+		name    string
+		pin     string
+		wantErr error
+	}{
+		{name: "matching pin", pin: fp},
+		{name: "uppercase pin", pin: strings.ToUpper(fp)},
+		{name: "colon-separated pin", pin: colonSeparated(fp)},
+		{name: "mismatching pin", pin: other, wantErr: ErrCertificatePinMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBaseWithOptions(
+				WithServerUrl(srv.URL),
+				WithApiKey("test"),
+				WithHTTPClient(srv.Client()),
+				WithPinnedCertFingerprint(tt.pin),
+			)
+			_, _, err := listSandboxesPage(context.Background(), b, "", 0)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCertificatePinningLeavesClientUntouched(t *testing.T) {
+	srv, _ := newPinTestServer(t)
+	client := srv.Client()
+	transport := client.Transport
+
+	newBaseWithOptions(
+		WithServerUrl(srv.URL),
+		WithApiKey("test"),
+		WithHTTPClient(client),
+		WithPinnedCertFingerprint(strings.Repeat("00", sha256.Size)),
+	)
+	if client.Transport != transport {
+		t.Fatal("pinning replaced the caller's transport")
+	}
+}
+
+func TestCertificatePinningRequiresHTTPS(t *testing.T) {
+	for _, url := range []string{"http://127.0.0.1:5555", "127.0.0.1:5555"} {
+		t.Run(url, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrCertificatePinningRequiresHTTPS) {
+					t.Fatalf("panic = %v, want %v", err, ErrCertificatePinningRequiresHTTPS)
+				}
+			}()
+			newBaseWithOptions(
+				WithServerUrl(url),
+				WithApiKey("test"),
+				WithPinnedCertFingerprint(strings.Repeat("ab", sha256.Size)),
+			)
+		})
+	}
+}
+
+func TestWithPinnedCertFingerprintRejectsInvalid(t *testing.T) {
+	for _, fp := range []string{"", "zz", strings.Repeat("ab", sha256.Size-1), strings.Repeat("ab", sha256.Size) + "ab"} {
+		t.Run(fp, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrInvalidCertFingerprint) {
+					t.Fatalf("panic = %v, want %v", err, ErrInvalidCertFingerprint)
+				}
+			}()
+			WithPinnedCertFingerprint(fp)(&baseMicroSandbox{})
+		})
+	}
+}
@@ -23,6 +23,7 @@ type (
 		OutputLines []outputLine `json:"output"`
 		Status      string       `json:"status"`
 		Language    string       `json:"language"`
+		Timing      *timingData  `json:"timing"`
 	}
 
 	outputLine struct {
@@ -104,3 +105,11 @@ func (ce CodeExecution) GetLanguage() string {
 	return ce.parsed.Language
 }
 
+// TimingBreakdown returns how the execution time was split between queueing, execution and output transfer.
+// Returns false if the server did not report a breakdown or the raw JSON could not be parsed.
+func (ce CodeExecution) TimingBreakdown() (Timing, bool) {
+	if !ce.parsedOK {
+		return Timing{}, false
+	}
+	return ce.parsed.Timing.toTiming()
+}
@@ -0,0 +1,69 @@
+package msb
+
+import "time"
+
+// Collector receives telemetry about the SDK's own RPC calls to the server, as opposed to
+// the sandbox resource usage reported by Metrics(). Implementations must be safe for concurrent use.
+// Use NewPrometheusCollector to export them as Prometheus metrics.
+type Collector interface {
+	// ObserveRPCDuration records the duration of a completed RPC call, whether it succeeded or not.
+	ObserveRPCDuration(method string, d time.Duration)
+	// IncRPCError counts a failed RPC call.
+	IncRPCError(method string)
+}
+
+// NoOpCollector is a collector that discards all observations.
+// This is used as the default collector.
+type NoOpCollector struct{}
+
+// ObserveRPCDuration discards the observation.
+func (NoOpCollector) ObserveRPCDuration(method string, d time.Duration) {}
+
+// IncRPCError discards the observation.
+func (NoOpCollector) IncRPCError(method string) {}
+
+// Minimal shapes of Prometheus metric vectors, so the adapter needs no dependency on the Prometheus client
+type (
+	promObserver   interface{ Observe(float64) }
+	promCounter    interface{ Inc() }
+	promVec[M any] interface {
+		WithLabelValues(lvs ...string) M
+	}
+)
+
+// NewPrometheusCollector adapts Prometheus metric vectors to a Collector: RPC durations are observed
+// in seconds on duration, and failed calls increment errs. Both vectors must have exactly one label,
+// which receives the RPC method name. Any type with the same method shapes may be used, so the SDK
+// itself does not depend on the Prometheus client library.
+//
+// Example:
+//
+//	rpcDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+//		Name: "msb_rpc_duration_seconds",
+//		Help: "Duration of microsandbox RPC calls.",
+//	}, []string{"method"})
+//	rpcErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "msb_rpc_errors_total",
+//		Help: "Number of failed microsandbox RPC calls.",
+//	}, []string{"method"})
+//	prometheus.MustRegister(rpcDuration, rpcErrors)
+//
+//	sandbox := msb.NewPythonSandbox(
+//		msb.WithMetricsCollector(msb.NewPrometheusCollector(rpcDuration, rpcErrors)),
+//	)
+func NewPrometheusCollector[O promObserver, C promCounter](duration promVec[O], errs promVec[C]) Collector {
+	return prometheusCollector[O, C]{duration: duration, errors: errs}
+}
+
+type prometheusCollector[O promObserver, C promCounter] struct {
+	duration promVec[O]
+	errors   promVec[C]
+}
+
+func (pc prometheusCollector[O, C]) ObserveRPCDuration(method string, d time.Duration) {
+	pc.duration.WithLabelValues(method).Observe(d.Seconds())
+}
+
+func (pc prometheusCollector[O, C]) IncRPCError(method string) {
+	pc.errors.WithLabelValues(method).Inc()
+}
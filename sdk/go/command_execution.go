@@ -20,6 +20,8 @@ type commandData struct {
 	Args        []string     `json:"args"`
 	ExitCode    int          `json:"exit_code"`
 	Success     bool         `json:"success"`
+	Timing      *timingData  `json:"timing"`
+	TimedOut    bool         `json:"timed_out"`
 }
 
 // GetOutput returns the standard output from command execution as a string.
@@ -90,4 +92,13 @@ func (ce CommandExecution) GetArgs() []string {
 		return nil
 	}
 	return ce.parsed.Args
-}
\ No newline at end of file
+}
+
+// TimingBreakdown returns how the execution time was split between queueing, execution and output transfer.
+// Returns false if the server did not report a breakdown or the raw JSON could not be parsed.
+func (ce CommandExecution) TimingBreakdown() (Timing, bool) {
+	if !ce.parsedOK {
+		return Timing{}, false
+	}
+	return ce.parsed.Timing.toTiming()
+}
@@ -0,0 +1,111 @@
+package msb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunTee polls a running command for new output every teePollInterval, backing off up to
+// teeMaxPollInterval while the command stays quiet.
+const (
+	teePollInterval    = 200 * time.Millisecond
+	teeMaxPollInterval = 2 * time.Second
+)
+
+func (cr commandRunner) RunTee(cmd string, args []string, localPath string) (CommandExecution, error) {
+	return cr.RunTeeContext(context.Background(), cmd, args, localPath)
+}
+
+func (cr commandRunner) RunTeeContext(ctx context.Context, cmd string, args []string, localPath string) (exec CommandExecution, err error) {
+	if cr.b.state.Load() != started {
+		return CommandExecution{}, ErrSandboxNotStarted
+	}
+
+	// Open the file before starting so a bad path never leaves a command running unlogged
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToOpenTeeFile, err)
+	}
+	f, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToOpenTeeFile, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("%w: %w", ErrFailedToWriteTeeFile, closeErr)
+		}
+	}()
+
+	// The command holds one concurrency slot from start until its last poll
+	ctx, release, err := cr.b.cfg.acquireSlot(ctx, string(methodSandboxCommandStart))
+	if err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+	defer release()
+
+	id, err := cr.b.rpcClient.startCommand(ctx, &cr.b.cfg, cmd, args)
+	if err != nil {
+		return CommandExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCommand, err))
+	}
+	// Giving up on the poll for any reason would otherwise leave the command running on the server
+	finished := false
+	defer func() {
+		if !finished {
+			cr.kill(id, true)
+		}
+	}()
+
+	var writeErr error
+	offset := 0
+	interval := teePollInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, ctx.Err())
+		case <-timer.C:
+		}
+
+		chunk, err := cr.b.rpcClient.getCommandOutput(ctx, &cr.b.cfg, id, offset)
+		if err != nil {
+			return CommandExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCommand, err))
+		}
+		offset += len(chunk.Lines)
+		for _, line := range chunk.Lines {
+			// Keep draining after a write failure so the command's result is still returned
+			if writeErr == nil {
+				_, writeErr = f.WriteString(line.Text + "\n")
+			}
+		}
+		if chunk.Done {
+			finished = true
+			exec = CommandExecution{Output: chunk.Result}
+			// Parse the output for convenience methods
+			if err := json.Unmarshal(chunk.Result, &exec.parsed); err == nil {
+				exec.parsedOK = true
+			}
+			if writeErr != nil {
+				return exec, fmt.Errorf("%w: %w", ErrFailedToWriteTeeFile, writeErr)
+			}
+			return exec, nil
+		}
+
+		if len(chunk.Lines) > 0 {
+			interval = teePollInterval
+		} else {
+			interval = min(interval*2, teeMaxPollInterval)
+		}
+		timer.Reset(interval)
+	}
+}
+
+// Tee-related errors
+var (
+	ErrFailedToOpenTeeFile  = errors.New("failed to open tee file")
+	ErrFailedToWriteTeeFile = errors.New("failed to write tee file")
+)
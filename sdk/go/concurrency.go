@@ -0,0 +1,53 @@
+package msb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// slotHeldKey marks a context whose RPCs run on behalf of an operation that already holds its
+// WithConcurrencyLimit slot, or that is exempt from the limit, so they must not queue again.
+type slotHeldKey struct{}
+
+// withHeldSlot returns a context whose RPCs skip the WithConcurrencyLimit queue.
+func withHeldSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, slotHeldKey{}, true)
+}
+
+func holdsSlot(ctx context.Context) bool {
+	held, _ := ctx.Value(slotHeldKey{}).(bool)
+	return held
+}
+
+// acquireSlot takes a WithConcurrencyLimit slot for the operation op, waiting until one is free,
+// ctx is done or the configured wait timeout passes. Operations spanning several RPCs call it once
+// and pass the returned context to every RPC, so they hold one slot until release is called.
+func (c *config) acquireSlot(ctx context.Context, op string) (slotCtx context.Context, release func(), err error) {
+	if c.inFlight == nil || holdsSlot(ctx) {
+		return ctx, func() {}, nil
+	}
+
+	var timeout <-chan time.Time
+	if c.inFlightWait > 0 {
+		t := time.NewTimer(c.inFlightWait)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case c.inFlight <- struct{}{}:
+		return withHeldSlot(ctx), func() { <-c.inFlight }, nil
+	case <-ctx.Done():
+		c.logger.Debug("Gave up waiting for concurrency slot", "operation", op, "error", ctx.Err())
+		return nil, nil, fmt.Errorf("%w: %w", ErrConcurrencyLimitWait, ctx.Err())
+	case <-timeout:
+		c.logger.Debug("Timed out waiting for concurrency slot", "operation", op, "wait", c.inFlightWait)
+		return nil, nil, fmt.Errorf("%w: waited %s", ErrConcurrencyLimitWait, c.inFlightWait)
+	}
+}
+
+// bypassesConcurrencyLimit reports whether method skips the WithConcurrencyLimit queue, so that
+// stopping a sandbox or killing a command is never stuck behind the operations it would end.
+func bypassesConcurrencyLimit(method rpcMethod) bool {
+	return isLifecycleMethod(method) || method == methodSandboxCommandKill
+}
@@ -1,18 +1,120 @@
 package msb
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
 type ReqIdProducer func() string
 
+// UnreachableFallback handles an operation that failed because the server could not be reached.
+// op is the name of the failed RPC method (e.g. "sandbox.repl.run"). The returned error replaces err;
+// returning nil suppresses it, except for lifecycle methods (see WithUnreachableFallback).
+type UnreachableFallback func(op string, err error) error
+
 type config struct {
 	serverUrl string
 	namespace string
-	name      string
+	name      atomic.Pointer[string] // updated by Rename while background readers (AppLogs, Processes().Stream) use it
 	apiKey    string
 	logger    Logger
+	collector Collector
 	reqIDPrd  ReqIdProducer
+	locale    string
+	timezone  string
+	certPin   string // normalized SHA-256 fingerprint of the pinned server certificate
+	fakeTime  *fakeTime
+	env       map[string]string // sandbox-wide environment
+	replEnv   map[string]string // REPL kernel environment, layered over env
+	security  *SecurityProfile
+	inFlight  chan struct{} // semaphore bounding in-flight RPCs, nil if unlimited
+
+	inFlightWait time.Duration // how long an RPC may queue for a slot in inFlight, <= 0 if indefinitely
+
+	appLogPath string    // application log file tailed by AppLogs
+	seedDirs   []seedDir // directories uploaded during Start, in option order
+
+	diagnosticsOnError  bool
+	unreachableFallback UnreachableFallback
+}
+
+// fakeTime describes the clock presented to processes inside the sandbox.
+type fakeTime struct {
+	start  time.Time
+	frozen bool
 }
 
 const (
 	defaultServerUrl    = "http://127.0.0.1:5555"
 	defaultNamespace    = "default"
 	defaultNameTemplate = "sandbox-%08x" // 8-char hex value (0-padded if shorter)
+
+	defaultConcurrencyWait = time.Minute
 )
+
+// sandboxName returns the sandbox's current name, empty if none was set.
+func (c *config) sandboxName() string {
+	if name := c.name.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+func (c *config) setSandboxName(name string) {
+	c.name.Store(&name)
+}
+
+// startEnvs returns the sandbox-wide environment variables forwarded at start, in KEY=VALUE form.
+// Variables derived from WithLocale and WithTimezone take precedence over those set via WithEnv.
+func (c *config) startEnvs() []string {
+	env := maps.Clone(c.env)
+	if env == nil {
+		env = map[string]string{}
+	}
+	if c.locale != "" {
+		env["LANG"] = c.locale
+		env["LC_ALL"] = c.locale
+	}
+	if c.timezone != "" {
+		env["TZ"] = c.timezone
+	}
+	return envList(env)
+}
+
+// envList flattens env into sorted KEY=VALUE pairs, or nil if env is empty.
+func envList(env map[string]string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+	envs := make([]string, 0, len(env))
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		envs = append(envs, k+"="+env[k])
+	}
+	return envs
+}
+
+// tzProbeZone is a zone present in every IANA database, used to tell an unknown timezone name
+// apart from a host without a timezone database.
+const tzProbeZone = "America/New_York"
+
+// validateTimezone checks the configured timezone against the IANA timezone database available
+// to the process: the host's, or one the application embeds by importing time/tzdata.
+// "Local" is rejected since it refers to the host's timezone, not one the sandbox can resolve.
+func (c *config) validateTimezone() error {
+	if c.timezone == "" {
+		return nil
+	}
+	if c.timezone == "Local" {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, c.timezone)
+	}
+	if _, err := time.LoadLocation(c.timezone); err != nil {
+		if _, probeErr := time.LoadLocation(tzProbeZone); probeErr != nil {
+			return fmt.Errorf("%w: cannot validate %q: %w", ErrTimezoneDatabaseUnavailable, c.timezone, err)
+		}
+		return fmt.Errorf("%w: %q: %w", ErrInvalidTimezone, c.timezone, err)
+	}
+	return nil
+}
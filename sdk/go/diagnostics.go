@@ -0,0 +1,98 @@
+package msb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Diagnostics is a best-effort snapshot of sandbox state captured when an operation failed.
+// Any part that could not be collected is left empty and its failure recorded in Errors.
+type Diagnostics struct {
+	CollectedAt time.Time     // When collection started
+	Logs        []string      // Most recent sandbox log lines, oldest first
+	Processes   []ProcessInfo // Processes running at failure time
+	Metrics     *Metrics      // Last resource metrics, nil if unavailable
+	Errors      []error       // Failures encountered while collecting
+}
+
+// DiagnosticError wraps an operation error together with diagnostics captured at failure time.
+// It is returned when WithDiagnosticsOnError is enabled; use errors.As to retrieve it.
+//
+// Example:
+//
+//	var diagErr *msb.DiagnosticError
+//	if errors.As(err, &diagErr) {
+//		d := diagErr.Diagnostics()
+//		fmt.Println(strings.Join(d.Logs, "\n"))
+//	}
+type DiagnosticError struct {
+	err  error
+	diag Diagnostics
+}
+
+func (e *DiagnosticError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DiagnosticError) Unwrap() error {
+	return e.err
+}
+
+// Diagnostics returns the sandbox state captured when the error occurred.
+func (e *DiagnosticError) Diagnostics() Diagnostics {
+	return e.diag
+}
+
+const (
+	// diagnosticsTimeout bounds collection so it never noticeably delays the original failure.
+	diagnosticsTimeout = 3 * time.Second
+	// diagnosticsLogLines is the number of recent log lines captured.
+	diagnosticsLogLines = 100
+)
+
+// withDiagnostics attaches diagnostics to err if WithDiagnosticsOnError is enabled.
+// Returns err unchanged otherwise, or if err is nil or a cancellation.
+func (b *baseMicroSandbox) withDiagnostics(err error) error {
+	if err == nil || !b.cfg.diagnosticsOnError || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &DiagnosticError{err: err, diag: b.collectDiagnostics()}
+}
+
+func (b *baseMicroSandbox) collectDiagnostics() Diagnostics {
+	d := Diagnostics{CollectedAt: time.Now()}
+	// Collection runs on behalf of the failed operation, which may still hold its concurrency slot
+	ctx, cancel := context.WithTimeout(withHeldSlot(context.Background()), diagnosticsTimeout)
+	defer cancel()
+
+	if logs, err := b.rpcClient.getLogs(ctx, &b.cfg, diagnosticsLogLines); err != nil {
+		d.Errors = append(d.Errors, fmt.Errorf("%w: %w", ErrFailedToGetLogs, err))
+	} else {
+		d.Logs = logs
+	}
+
+	if procs, err := (processReader{b}).list(ctx); err != nil {
+		d.Errors = append(d.Errors, err)
+	} else {
+		d.Processes = procs
+	}
+
+	if metrics, err := b.rpcClient.getMetrics(ctx, &b.cfg); err != nil {
+		d.Errors = append(d.Errors, fmt.Errorf("%w: %w", ErrFailedToGetMetrics, err))
+	} else {
+		m := metrics.toMetrics()
+		d.Metrics = &m
+	}
+
+	if len(d.Errors) > 0 {
+		b.cfg.logger.Debug("Diagnostics collected partially", "sandbox", b.cfg.sandboxName(), "errors", errors.Join(d.Errors...))
+	}
+	return d
+}
+
+// Diagnostics-related errors
+var (
+	ErrFailedToGetLogs = errors.New("failed to get logs")
+)
@@ -0,0 +1,101 @@
+package msb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffField identifies a field compared by DiffExecutions.
+type DiffField string
+
+// Fields compared by DiffExecutions, in reporting order.
+const (
+	DiffFieldOutput   DiffField = "output"
+	DiffFieldError    DiffField = "error"
+	DiffFieldStatus   DiffField = "status"
+	DiffFieldLanguage DiffField = "language"
+)
+
+// ExecutionDiff reports the fields that differ between two code executions.
+// An empty diff means both executions are considered equivalent.
+type ExecutionDiff struct {
+	Fields []FieldDiff // Differing fields, in the order they were compared
+}
+
+// FieldDiff holds the values of a single field that differs between two executions.
+type FieldDiff struct {
+	Field DiffField // Name of the differing field
+	A     string    // Value from the first execution
+	B     string    // Value from the second execution
+}
+
+// Equal reports whether no differences were found.
+func (d ExecutionDiff) Equal() bool {
+	return len(d.Fields) == 0
+}
+
+// Has reports whether the given field differs between the two executions.
+func (d ExecutionDiff) Has(field DiffField) bool {
+	for _, f := range d.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffOption configures how DiffExecutions compares executions.
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	normalizeWhitespace bool
+}
+
+// WithNormalizedWhitespace makes DiffExecutions ignore whitespace-only differences in
+// output and error text: leading/trailing whitespace is trimmed and inner runs of
+// whitespace are collapsed to a single space before comparing.
+func WithNormalizedWhitespace() DiffOption {
+	return func(c *diffConfig) {
+		c.normalizeWhitespace = true
+	}
+}
+
+// DiffExecutions compares the output, error output, status and language of two code executions
+// and reports which of them differ. Useful for regression testing generated code across runs.
+// Returns ErrExecutionNotParsed if either execution could not be parsed.
+func DiffExecutions(a, b CodeExecution, options ...DiffOption) (ExecutionDiff, error) {
+	if !a.parsedOK || !b.parsedOK {
+		return ExecutionDiff{}, fmt.Errorf("%w: cannot diff unparsed execution", ErrExecutionNotParsed)
+	}
+
+	var cfg diffConfig
+	for _, opt := range options {
+		opt(&cfg)
+	}
+
+	text := func(s string) string {
+		if cfg.normalizeWhitespace {
+			return strings.Join(strings.Fields(s), " ")
+		}
+		return s
+	}
+
+	// Parsed executions never fail these getters
+	outA, _ := a.GetOutput()
+	outB, _ := b.GetOutput()
+	errA, _ := a.GetError()
+	errB, _ := b.GetError()
+
+	var diff ExecutionDiff
+	for _, f := range []FieldDiff{
+		{Field: DiffFieldOutput, A: text(outA), B: text(outB)},
+		{Field: DiffFieldError, A: text(errA), B: text(errB)},
+		{Field: DiffFieldStatus, A: a.GetStatus(), B: b.GetStatus()},
+		{Field: DiffFieldLanguage, A: a.GetLanguage(), B: b.GetLanguage()},
+	} {
+		if f.A != f.B {
+			diff.Fields = append(diff.Fields, f)
+		}
+	}
+	return diff, nil
+}
@@ -0,0 +1,42 @@
+package msb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fakeTimeTolerance is how far the guest clock may be off the requested fake time, covering boot
+// time and clock granularity, before Start concludes the fake time was not applied.
+const fakeTimeTolerance = time.Minute
+
+// verifyFakeTime reads the clock inside the sandbox and fails with ErrFakeTimeUnsupported unless it
+// reflects the configured fake time. The server may silently ignore the fake_time start field, so
+// the guest clock is the only reliable signal.
+func (s starter) verifyFakeTime(ctx context.Context, launchedAt time.Time) error {
+	ft := s.b.cfg.fakeTime
+	if ft == nil {
+		return nil
+	}
+
+	out, err := s.b.runGuestCommand(ctx, "date", "-u", "+%s")
+	if err != nil {
+		return fmt.Errorf("%w: reading guest clock: %w", ErrFakeTimeUnsupported, err)
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: unexpected guest clock output %q", ErrFakeTimeUnsupported, out)
+	}
+
+	want := ft.start
+	if !ft.frozen {
+		want = want.Add(time.Since(launchedAt))
+	}
+	if got := time.Unix(secs, 0); got.Sub(want).Abs() > fakeTimeTolerance {
+		return fmt.Errorf("%w: guest clock reads %s, expected about %s", ErrFakeTimeUnsupported,
+			got.UTC().Format(time.RFC3339), want.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package msb
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // LangSandBox provides a complete sandbox interface for a specific programming language.
 // It combines lifecycle management (Start/Stop) with execution capabilities (Code/Command)
@@ -21,9 +24,16 @@ import "errors"
 type LangSandBox interface {
 	Starter
 	Stopper
+	FlushStopper
+	Renamer
+	LimitsReader
+	KernelInfoReader
+	AppLogReader
 	Code() CodeRunner
 	Command() CommandRunner
 	Metrics() MetricsReader
+	Files() FileManager
+	Processes() ProcessReader
 }
 
 var _ LangSandBox = (*langSandbox)(nil)
@@ -76,7 +86,27 @@ func (ls *langSandbox) Start(image string, memoryMB int, cpus int) error {
 }
 
 func (ls *langSandbox) Stop() error {
-	return stopper{ls.b}.Stop()
+	return stopper{ls.b, ls.l}.Stop()
+}
+
+func (ls *langSandbox) FlushAndStop() (CodeExecution, error) {
+	return stopper{ls.b, ls.l}.FlushAndStop()
+}
+
+func (ls *langSandbox) Rename(newName string) error {
+	return renamer{ls.b}.Rename(newName)
+}
+
+func (ls *langSandbox) Limits() (ResourceLimits, error) {
+	return limitsReader{ls.b}.Limits()
+}
+
+func (ls *langSandbox) KernelInfo() (KernelInfo, error) {
+	return kernelInfoReader{ls.b}.KernelInfo()
+}
+
+func (ls *langSandbox) AppLogs(ctx context.Context, format LogFormat) (<-chan LogRecord, error) {
+	return appLogReader{ls.b}.AppLogs(ctx, format)
 }
 
 func (ls *langSandbox) Code() CodeRunner {
@@ -91,6 +121,14 @@ func (ls *langSandbox) Metrics() MetricsReader {
 	return metricsReader{ls.b}
 }
 
+func (ls *langSandbox) Files() FileManager {
+	return fileManager{ls.b}
+}
+
+func (ls *langSandbox) Processes() ProcessReader {
+	return processReader{ls.b}
+}
+
 type progLang int
 
 const (
@@ -122,6 +160,25 @@ func (p progLang) DefaultImage() string {
 	}
 }
 
+// Language selects the programming language of a sandbox created via StartMany.
+type Language int
+
+const (
+	LanguagePython Language = iota + 1
+	LanguageNodeJs
+)
+
+func (l Language) progLang() (progLang, error) {
+	switch l {
+	case LanguagePython:
+		return langPython, nil
+	case LanguageNodeJs:
+		return langNodeJs, nil
+	default:
+		return langUnspecified, ErrLanguageMustBeSpecified
+	}
+}
+
 // Language-related errors
 var (
 	ErrUnknownLanguage = errors.New("unknown language")
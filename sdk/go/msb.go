@@ -36,12 +36,25 @@
 //		log.Fatal(err)
 //	}
 //	fmt.Printf("CPU: %.2f%%, Memory: %d MiB\n", metrics.CPU, metrics.MemoryMiB)
+//
+// # Server Compatibility
+//
+// microsandbox-server 0.2.6 implements only sandbox start/stop, metrics, REPL runs and command runs.
+// Every other feature relies on a server implementing the corresponding sandbox.* method; against
+// one that does not, it fails with an error matching ErrMethodUnsupported.
 package msb
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
 )
 
 // Core sandbox interfaces
@@ -60,11 +73,58 @@ type (
 		Stop() error
 	}
 
+	// FlushStopper shuts a sandbox down without losing the output of a running REPL cell.
+	FlushStopper interface {
+		// FlushAndStop asks the REPL kernel to flush any buffered output of the running cell,
+		// then terminates the sandbox. The flushed output is returned so it is not lost on shutdown.
+		// The flush is bounded by a short timeout; if the kernel does not respond in time, the
+		// sandbox is stopped anyway and an empty execution is returned.
+		FlushAndStop() (CodeExecution, error)
+	}
+
+	// Renamer relabels a running sandbox.
+	Renamer interface {
+		// Rename changes the sandbox's name on the server without restarting it, keeping its state.
+		// Names must be 1-63 characters of letters, digits, '-' or '_', starting with a letter
+		// or digit; otherwise ErrInvalidSandboxName is returned. ErrSandboxNameTaken is returned if
+		// another sandbox in the namespace already uses the name.
+		Rename(newName string) error
+	}
+
+	// LimitsReader reports the resource limits enforced on the sandbox.
+	LimitsReader interface {
+		// Limits returns the memory, CPU, disk and ulimit caps currently in effect, as read
+		// from the sandbox's cgroup and process limits. Unlike the values passed to Start,
+		// these reflect what the kernel actually enforces.
+		Limits() (ResourceLimits, error)
+	}
+
+	// KernelInfoReader reports the runtime a sandbox booted with.
+	KernelInfoReader interface {
+		// KernelInfo returns the guest kernel version, architecture and VMM version of the sandbox.
+		// Useful when diagnosing platform-specific issues such as KVM or libkrun failures.
+		KernelInfo() (KernelInfo, error)
+	}
+
+	// AppLogReader streams structured records from the application running in the sandbox.
+	AppLogReader interface {
+		// AppLogs tails the application log file configured via WithAppLogFile, parsing each line
+		// in the given format into a LogRecord. Lines that cannot be parsed are emitted as raw-message
+		// records. The channel is closed once ctx is cancelled.
+		// Only lines appended after the call are emitted; if the file cannot be read at first (e.g.
+		// it does not exist yet), it is read from its beginning once it can.
+		AppLogs(ctx context.Context, format LogFormat) (<-chan LogRecord, error)
+	}
+
 	// CodeRunner executes code in the sandbox's REPL environment.
 	CodeRunner interface {
 		// Run executes the provided code and returns detailed execution results.
 		// The sandbox must be started before calling this method.
 		Run(code string) (CodeExecution, error)
+		// RunCell executes the provided code and returns the result as a notebook cell,
+		// with outputs ordered and typed as streams, results, displays and errors.
+		// The sandbox must be started before calling this method.
+		RunCell(code string) (NotebookCell, error)
 	}
 
 	// CommandRunner executes shell commands in the sandbox.
@@ -72,6 +132,39 @@ type (
 		// Run executes a shell command with the given arguments.
 		// The sandbox must be started before calling this method.
 		Run(cmd string, args []string) (CommandExecution, error)
+		// Pipe runs the stages as a single server-side pipeline, feeding the stdout of each
+		// stage into the stdin of the next, without going through a shell.
+		// The result carries the final stage's output and the exit code of the first failing
+		// stage, or of the last stage if all succeed.
+		Pipe(stages ...Command) (CommandExecution, error)
+		// RunWithDeadline executes a command that the server terminates once ctx's deadline passes.
+		// If killChildren is true, the command's whole process group is killed, so children that
+		// would otherwise outlive it are cleaned up too. On timeout, the output produced so far is
+		// returned along with an error matching context.DeadlineExceeded. If the server has not ended
+		// the command a few seconds past the deadline, it is asked explicitly to kill it, honoring
+		// killChildren. Without a deadline on ctx, the command runs until it exits. If ctx is cancelled first, the server is asked to kill the
+		// command (and its process group if killChildren is true) before an error matching
+		// context.Canceled is returned.
+		RunWithDeadline(ctx context.Context, cmd string, args []string, killChildren bool) (CommandExecution, error)
+		// RunTee executes a command like Run, while also appending its stdout and stderr lines to the
+		// local file at localPath as they arrive. Parent directories are created as needed. If the file
+		// cannot be opened, ErrFailedToOpenTeeFile is returned before the command starts.
+		RunTee(cmd string, args []string, localPath string) (CommandExecution, error)
+		// RunTeeContext is like RunTee, but gives up when ctx is done, returning an error matching
+		// ctx's error. Whenever it returns before the command finished, including on a failure to
+		// fetch its output, the command and its process group are killed on the server.
+		RunTeeContext(ctx context.Context, cmd string, args []string, localPath string) (CommandExecution, error)
+		// RunShellScript uploads script to a temporary file, makes it executable and runs it,
+		// honoring its shebang line or defaulting to /bin/sh if it has none. Stdout and stderr are
+		// combined, in order, into the execution's output. The temporary file is removed afterwards,
+		// including when the run fails.
+		RunShellScript(script string) (CommandExecution, error)
+	}
+
+	// Command describes a single stage of a command pipeline.
+	Command struct {
+		Cmd  string   // Executable to run
+		Args []string // Arguments passed to the executable
 	}
 
 	// MetricsReader provides access to sandbox resource metrics.
@@ -88,6 +181,50 @@ type (
 		IsRunning() (bool, error)
 	}
 
+	// FileManager provides access to files inside the sandbox.
+	FileManager interface {
+		// CrashArtifacts lists core dumps and other crash artifacts captured by the server.
+		// Returns an empty slice when there are none.
+		CrashArtifacts() ([]FileInfo, error)
+		// DownloadCrashArtifact streams the contents of the crash artifact at the given path,
+		// as reported by CrashArtifacts, to w in fixed-size chunks so large core dumps are never
+		// held in memory at once. Returns the number of bytes written.
+		DownloadCrashArtifact(path string, w io.Writer) (int64, error)
+		// WriteFile writes data to the file at path inside the sandbox, creating it with
+		// permissions perm (before umask) or truncating it if it already exists.
+		WriteFile(path string, data []byte, perm fs.FileMode) error
+	}
+
+	// FileInfo describes a file inside the sandbox.
+	FileInfo struct {
+		Name    string    // Base name of the file
+		Path    string    // Absolute path inside the sandbox
+		Size    int64     // Size in bytes
+		ModTime time.Time // Last modification time
+	}
+
+	// ResourceLimits contains the effective resource caps of a sandbox.
+	// Any value of -1 means the resource is unlimited.
+	ResourceLimits struct {
+		MemoryMiB int               // Memory limit in mebibytes
+		CPUs      float64           // CPU quota in cores (e.g. 1.5)
+		DiskBytes int               // Disk limit in bytes
+		Ulimits   map[string]Ulimit // Process limits keyed by resource name (e.g. "nofile", "nproc")
+	}
+
+	// Ulimit holds the soft and hard values of a single process limit. -1 means unlimited.
+	Ulimit struct {
+		Soft int64
+		Hard int64
+	}
+
+	// KernelInfo describes the runtime a sandbox is running on.
+	KernelInfo struct {
+		KernelVersion string // Guest kernel release (as reported by uname -r)
+		Architecture  string // Guest machine architecture (e.g. "x86_64", "aarch64")
+		VMMVersion    string // Version of libkrun, the VMM hosting the sandbox
+	}
+
 	// Metrics contains resource usage information for a sandbox.
 	Metrics struct {
 		Name      string  // Sandbox name
@@ -106,25 +243,67 @@ type starter struct {
 }
 
 func (s starter) Start(image string, memoryMB int, cpus int) error {
+	return s.start(context.Background(), image, memoryMB, cpus)
+}
+
+func (s starter) start(ctx context.Context, image string, memoryMB int, cpus int) error {
 	if s.b.state.Load() == started {
 		return ErrSandboxAlreadyStarted
 	}
+	// The checks and seeding Start runs share its exemptions: no concurrency limit, no suppression
+	ctx = withLifecycleCall(withHeldSlot(ctx))
 	if memoryMB <= 0 {
 		memoryMB = 512
 	}
 	if cpus <= 0 {
 		cpus = 1
 	}
-	err := s.b.rpcClient.startSandbox(context.Background(), &s.b.cfg, image, memoryMB, cpus)
-	if err != nil {
+	if err := s.b.cfg.validateTimezone(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToStartSandbox, err)
+	}
+	if err := s.b.cfg.validateSecurityProfile(); err != nil {
 		return fmt.Errorf("%w: %w", ErrFailedToStartSandbox, err)
 	}
+	if err := s.b.cfg.checkSeedDirs(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToStartSandbox, err)
+	}
+	launchedAt := time.Now()
+	err := s.b.rpcClient.startSandbox(ctx, &s.b.cfg, image, memoryMB, cpus)
+	if hasRPCCode(err, rpcCodeFakeTimeUnsupported) {
+		return s.b.withDiagnostics(fmt.Errorf("%w: %w: %w", ErrFailedToStartSandbox, ErrFakeTimeUnsupported, err))
+	}
+	if hasRPCCode(err, rpcCodeSecurityProfileUnsupported) {
+		return s.b.withDiagnostics(fmt.Errorf("%w: %w: %w", ErrFailedToStartSandbox, ErrSecurityProfileUnsupported, err))
+	}
+	if err != nil {
+		return s.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToStartSandbox, err))
+	}
+	if err := s.prepare(ctx, launchedAt); err != nil {
+		// Diagnostics must be collected while the sandbox is still up
+		err = s.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToStartSandbox, err))
+		if stopErr := s.b.rpcClient.stopSandbox(context.WithoutCancel(ctx), &s.b.cfg); stopErr != nil {
+			s.b.cfg.logger.Error("Failed to stop sandbox after start-up checks failed", "sandbox", s.b.cfg.sandboxName(), "error", stopErr)
+		}
+		return err
+	}
 	s.b.state.Store(started)
 	return nil
 }
 
+// prepare verifies and seeds a freshly launched sandbox before Start reports success.
+func (s starter) prepare(ctx context.Context, launchedAt time.Time) error {
+	if err := s.verifySecurityProfile(ctx); err != nil {
+		return err
+	}
+	if err := s.verifyFakeTime(ctx, launchedAt); err != nil {
+		return err
+	}
+	return s.seed(ctx)
+}
+
 type stopper struct {
 	b *baseMicroSandbox
+	l progLang
 }
 
 func (s stopper) Stop() error {
@@ -140,6 +319,108 @@ func (s stopper) Stop() error {
 	return nil
 }
 
+// replFlushTimeout bounds how long FlushAndStop waits on a possibly wedged kernel.
+const replFlushTimeout = 2 * time.Second
+
+func (s stopper) FlushAndStop() (CodeExecution, error) {
+	if s.b.state.Load() == off {
+		return CodeExecution{}, ErrSandboxNotStarted
+	}
+
+	var exec CodeExecution
+	ctx, cancel := context.WithTimeout(context.Background(), replFlushTimeout)
+	result, err := s.b.rpcClient.flushRepl(ctx, &s.b.cfg, s.l)
+	cancel()
+	if err != nil {
+		// Best-effort: losing the tail is preferable to never stopping
+		s.b.cfg.logger.Error("Failed to flush REPL output before stop", "sandbox", s.b.cfg.sandboxName(), "error", err)
+	} else {
+		exec.Output = result.output
+		// Parse the output for convenience methods
+		if err := json.Unmarshal(result.output, &exec.parsed); err == nil {
+			exec.parsedOK = true
+		}
+	}
+
+	return exec, s.Stop()
+}
+
+type renamer struct {
+	b *baseMicroSandbox
+}
+
+// sandboxNamePattern matches valid sandbox names; generated names (see defaultNameTemplate) always match.
+var sandboxNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+func (r renamer) Rename(newName string) error {
+	if r.b.state.Load() != started {
+		return ErrSandboxNotStarted
+	}
+	if !sandboxNamePattern.MatchString(newName) {
+		return fmt.Errorf("%w: %q", ErrInvalidSandboxName, newName)
+	}
+
+	ctx := context.Background()
+	err := r.b.rpcClient.renameSandbox(ctx, &r.b.cfg, newName)
+	if hasRPCCode(err, rpcCodeSandboxNameTaken) {
+		return fmt.Errorf("%w: %w: %q", ErrFailedToRenameSandbox, ErrSandboxNameTaken, newName)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToRenameSandbox, err)
+	}
+	r.b.cfg.setSandboxName(newName)
+	return nil
+}
+
+type limitsReader struct {
+	b *baseMicroSandbox
+}
+
+func (lr limitsReader) Limits() (ResourceLimits, error) {
+	if lr.b.state.Load() != started {
+		return ResourceLimits{}, ErrSandboxNotStarted
+	}
+
+	ctx := context.Background()
+	limits, err := lr.b.rpcClient.getLimits(ctx, &lr.b.cfg)
+	if err != nil {
+		return ResourceLimits{}, fmt.Errorf("%w: %w", ErrFailedToGetLimits, err)
+	}
+
+	ulimits := make(map[string]Ulimit, len(limits.Ulimits))
+	for name, u := range limits.Ulimits {
+		ulimits[name] = Ulimit{Soft: u.Soft, Hard: u.Hard}
+	}
+	return ResourceLimits{
+		MemoryMiB: limits.MemoryLimit,
+		CPUs:      limits.CPULimit,
+		DiskBytes: limits.DiskLimit,
+		Ulimits:   ulimits,
+	}, nil
+}
+
+type kernelInfoReader struct {
+	b *baseMicroSandbox
+}
+
+func (kr kernelInfoReader) KernelInfo() (KernelInfo, error) {
+	if kr.b.state.Load() != started {
+		return KernelInfo{}, ErrSandboxNotStarted
+	}
+
+	ctx := context.Background()
+	info, err := kr.b.rpcClient.getKernelInfo(ctx, &kr.b.cfg)
+	if err != nil {
+		return KernelInfo{}, fmt.Errorf("%w: %w", ErrFailedToGetKernelInfo, err)
+	}
+
+	return KernelInfo{
+		KernelVersion: info.KernelVersion,
+		Architecture:  info.Arch,
+		VMMVersion:    info.VMMVersion,
+	}, nil
+}
+
 type codeRunner struct {
 	b *baseMicroSandbox
 	l progLang
@@ -152,7 +433,7 @@ func (cr codeRunner) Run(code string) (CodeExecution, error) {
 	ctx := context.Background()
 	result, err := cr.b.rpcClient.runRepl(ctx, &cr.b.cfg, cr.l, code)
 	if err != nil {
-		return CodeExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCode, err)
+		return CodeExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCode, err))
 	}
 
 	exec := CodeExecution{Output: result.output}
@@ -164,19 +445,160 @@ func (cr codeRunner) Run(code string) (CodeExecution, error) {
 	return exec, nil
 }
 
+func (cr codeRunner) RunCell(code string) (NotebookCell, error) {
+	if cr.b.state.Load() != started {
+		return NotebookCell{}, ErrSandboxNotStarted
+	}
+	ctx := context.Background()
+	result, err := cr.b.rpcClient.runReplCell(ctx, &cr.b.cfg, cr.l, code)
+	if err != nil {
+		return NotebookCell{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCode, err))
+	}
+
+	var parsed cellData
+	if err := json.Unmarshal(result.output, &parsed); err != nil {
+		return NotebookCell{}, fmt.Errorf("%w: %w", ErrExecutionNotParsed, err)
+	}
+	return parsed.toNotebookCell(code), nil
+}
+
 type commandRunner struct {
 	b *baseMicroSandbox
 }
 
 func (cr commandRunner) Run(cmd string, args []string) (CommandExecution, error) {
+	return cr.run(context.Background(), cmd, args)
+}
+
+func (cr commandRunner) run(ctx context.Context, cmd string, args []string) (CommandExecution, error) {
 	if cr.b.state.Load() != started {
 		return CommandExecution{}, ErrSandboxNotStarted
 	}
-	ctx := context.Background()
 	result, err := cr.b.rpcClient.runCommand(ctx, &cr.b.cfg, cmd, args)
 	if err != nil {
+		return CommandExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCommand, err))
+	}
+
+	exec := CommandExecution{Output: result.output}
+	// Parse the output for convenience methods
+	if err := json.Unmarshal(result.output, &exec.parsed); err == nil {
+		exec.parsedOK = true
+	}
+
+	return exec, nil
+}
+
+// runGuestCommand runs an SDK-internal command inside the sandbox regardless of its lifecycle state
+// and returns its standard output, failing if the command did not succeed.
+func (b *baseMicroSandbox) runGuestCommand(ctx context.Context, cmd string, args ...string) (string, error) {
+	result, err := b.rpcClient.runCommand(ctx, &b.cfg, cmd, args)
+	if err != nil {
+		return "", err
+	}
+	exec := CommandExecution{Output: result.output}
+	if err := json.Unmarshal(result.output, &exec.parsed); err == nil {
+		exec.parsedOK = true
+	}
+	if !exec.IsSuccess() {
+		stderr, _ := exec.GetError()
+		return "", fmt.Errorf("%s failed with exit code %d: %s", cmd, exec.GetExitCode(), stderr)
+	}
+	return exec.GetOutput()
+}
+
+// commandDeadlineGrace is how long RunWithDeadline keeps waiting past the deadline for the server
+// to terminate the command and return its partial output.
+const commandDeadlineGrace = 5 * time.Second
+
+func (cr commandRunner) RunWithDeadline(ctx context.Context, cmd string, args []string, killChildren bool) (CommandExecution, error) {
+	if cr.b.state.Load() != started {
+		return CommandExecution{}, ErrSandboxNotStarted
+	}
+	if err := ctx.Err(); err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+
+	// The execution ID is chosen here so the command can be killed while the request is in flight
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
 		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
 	}
+	executionID := fmt.Sprintf("msb-%x", id)
+
+	// Queueing respects ctx; the server's budget is computed only once the slot is held
+	ctx, release, err := cr.b.cfg.acquireSlot(ctx, string(methodSandboxCommandRun))
+	if err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+	defer release()
+
+	// The server enforces the deadline; the request itself must outlive it to receive the
+	// partial output, while still being aborted if ctx is cancelled outright.
+	var deadline time.Duration
+	var rpcCtx context.Context
+	var cancel context.CancelFunc
+	if d, ok := ctx.Deadline(); ok {
+		deadline = max(time.Until(d), time.Millisecond) // the server deadline has millisecond resolution
+		rpcCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), deadline+commandDeadlineGrace)
+	} else {
+		rpcCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			// Aborting the request alone would leave the command running on the server
+			cr.kill(executionID, killChildren)
+			cancel()
+		}
+	})
+	defer stop()
+
+	result, err := cr.b.rpcClient.runCommandWithDeadline(rpcCtx, &cr.b.cfg, executionID, cmd, args, deadline, killChildren)
+	if err != nil {
+		if errors.Is(rpcCtx.Err(), context.DeadlineExceeded) {
+			// The server did not end the command by its deadline (it may not support deadline_ms at all)
+			cr.kill(executionID, killChildren)
+		}
+		return CommandExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCommand, err))
+	}
+
+	exec := CommandExecution{Output: result.output}
+	// Parse the output for convenience methods
+	if err := json.Unmarshal(result.output, &exec.parsed); err == nil {
+		exec.parsedOK = true
+	}
+	if exec.parsed.TimedOut {
+		return exec, fmt.Errorf("%w: %w", ErrFailedToRunCommand, context.DeadlineExceeded)
+	}
+
+	return exec, nil
+}
+
+// commandKillTimeout bounds how long an abandoned command waits for the server to acknowledge its kill.
+const commandKillTimeout = 5 * time.Second
+
+// kill asks the server to terminate a running command, and its process group if killGroup is set.
+// Failures are only logged: the caller is already returning the error that ended the command.
+func (cr commandRunner) kill(executionID string, killGroup bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandKillTimeout)
+	defer cancel()
+	if err := cr.b.rpcClient.killCommand(ctx, &cr.b.cfg, executionID, killGroup); err != nil {
+		cr.b.cfg.logger.Error("Failed to kill cancelled command", "sandbox", cr.b.cfg.sandboxName(), "execution_id", executionID, "error", err)
+	}
+}
+
+func (cr commandRunner) Pipe(stages ...Command) (CommandExecution, error) {
+	if cr.b.state.Load() != started {
+		return CommandExecution{}, ErrSandboxNotStarted
+	}
+	if len(stages) == 0 {
+		return CommandExecution{}, ErrEmptyPipeline
+	}
+	ctx := context.Background()
+	result, err := cr.b.rpcClient.runPipeline(ctx, &cr.b.cfg, stages)
+	if err != nil {
+		return CommandExecution{}, cr.b.withDiagnostics(fmt.Errorf("%w: %w", ErrFailedToRunCommand, err))
+	}
 
 	exec := CommandExecution{Output: result.output}
 	// Parse the output for convenience methods
@@ -187,6 +609,45 @@ func (cr commandRunner) Run(cmd string, args []string) (CommandExecution, error)
 	return exec, nil
 }
 
+// scriptShebang is prepended to scripts that do not declare an interpreter.
+const scriptShebang = "#!/bin/sh\n"
+
+func (cr commandRunner) RunShellScript(script string) (CommandExecution, error) {
+	if cr.b.state.Load() != started {
+		return CommandExecution{}, ErrSandboxNotStarted
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+	path := fmt.Sprintf("/tmp/msb-script-%x", suffix)
+
+	if !strings.HasPrefix(script, "#!") {
+		script = scriptShebang + script
+	}
+
+	// Upload, run and cleanup hold a single concurrency slot
+	ctx, release, err := cr.b.cfg.acquireSlot(context.Background(), string(methodSandboxCommandRun))
+	if err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+	defer release()
+
+	// Registered before the write, which may fail after the server already created the file
+	defer func() {
+		if _, err := cr.run(ctx, "rm", []string{"-f", path}); err != nil {
+			cr.b.cfg.logger.Error("Failed to remove script file", "sandbox", cr.b.cfg.sandboxName(), "path", path, "error", err)
+		}
+	}()
+	if err := (fileManager{cr.b}).writeFile(ctx, path, []byte(script), 0o700); err != nil {
+		return CommandExecution{}, fmt.Errorf("%w: %w", ErrFailedToRunCommand, err)
+	}
+
+	// exec keeps the script's exit code; 2>&1 merges stderr into stdout in order
+	return cr.run(ctx, "/bin/sh", []string{"-c", `exec "$0" 2>&1`, path})
+}
+
 type metricsReader struct {
 	b *baseMicroSandbox
 }
@@ -202,14 +663,7 @@ func (mr metricsReader) All() (Metrics, error) {
 		return Metrics{}, fmt.Errorf("%w: %w", ErrFailedToGetMetrics, err)
 	}
 
-	return Metrics{
-		Name:      metrics.Name,
-		Namespace: metrics.Namespace,
-		IsRunning: metrics.Running,
-		CPU:       metrics.CPUUsage,
-		MemoryMiB: metrics.MemoryUsage,
-		DiskBytes: metrics.DiskUsage,
-	}, nil
+	return metrics.toMetrics(), nil
 }
 
 func (mr metricsReader) CPU() (float64, error) {
@@ -243,3 +697,73 @@ func (mr metricsReader) IsRunning() (bool, error) {
 	}
 	return metrics.IsRunning, nil
 }
+
+type fileManager struct {
+	b *baseMicroSandbox
+}
+
+func (fm fileManager) CrashArtifacts() ([]FileInfo, error) {
+	if fm.b.state.Load() != started {
+		return nil, ErrSandboxNotStarted
+	}
+
+	ctx := context.Background()
+	files, err := fm.b.rpcClient.listCrashArtifacts(ctx, &fm.b.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListCrashArtifacts, err)
+	}
+
+	artifacts := make([]FileInfo, 0, len(files))
+	for _, f := range files {
+		artifacts = append(artifacts, f.toFileInfo())
+	}
+	return artifacts, nil
+}
+
+// crashChunkSize bounds how much of a crash artifact is requested (and held in memory) per RPC.
+const crashChunkSize = 1 << 20
+
+func (fm fileManager) DownloadCrashArtifact(path string, w io.Writer) (int64, error) {
+	if fm.b.state.Load() != started {
+		return 0, ErrSandboxNotStarted
+	}
+
+	// The download holds one concurrency slot across all of its chunks
+	ctx, release, err := fm.b.cfg.acquireSlot(context.Background(), string(methodSandboxCrashGet))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToDownloadCrashArtifact, err)
+	}
+	defer release()
+
+	var written int64
+	for {
+		chunk, err := fm.b.rpcClient.getCrashArtifactChunk(ctx, &fm.b.cfg, path, written, crashChunkSize)
+		if err != nil {
+			return written, fmt.Errorf("%w: %w", ErrFailedToDownloadCrashArtifact, err)
+		}
+		n, err := w.Write(chunk.Content)
+		written += int64(n)
+		if err != nil {
+			return written, fmt.Errorf("%w: %w", ErrFailedToDownloadCrashArtifact, err)
+		}
+		// An empty chunk without EOF would loop forever; treat it as the end of the artifact
+		if chunk.EOF || len(chunk.Content) == 0 {
+			return written, nil
+		}
+	}
+}
+
+func (fm fileManager) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return fm.writeFile(context.Background(), path, data, perm)
+}
+
+func (fm fileManager) writeFile(ctx context.Context, path string, data []byte, perm fs.FileMode) error {
+	if fm.b.state.Load() != started {
+		return ErrSandboxNotStarted
+	}
+
+	if err := fm.b.rpcClient.writeFile(ctx, &fm.b.cfg, path, 0, data, perm); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToWriteFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package msb
+
+import "encoding/json"
+
+// NotebookCell represents a code execution in the shape expected by Jupyter-compatible front-ends.
+type NotebookCell struct {
+	Source         string       // Code that was executed
+	Outputs        []CellOutput // Outputs in the order they were produced
+	ExecutionCount int          // REPL execution counter for this cell
+	Status         string       // Execution status (e.g., "success", "error")
+}
+
+// CellOutputType is the kind of a notebook cell output, using nbformat's output_type names.
+type CellOutputType string
+
+const (
+	CellOutputStream  CellOutputType = "stream"         // Text written to stdout or stderr
+	CellOutputResult  CellOutputType = "execute_result" // Value of the cell's last expression
+	CellOutputDisplay CellOutputType = "display_data"   // Rich output emitted via a display call
+	CellOutputError   CellOutputType = "error"          // Uncaught exception
+)
+
+// CellOutput is a single output of a notebook cell. Which fields are set depends on Type:
+// Name and Text for streams, Data for results and displays, and the Error* fields for errors.
+type CellOutput struct {
+	Type           CellOutputType             // Kind of output
+	Name           string                     // Stream name ("stdout" or "stderr")
+	Text           string                     // Stream text
+	Data           map[string]json.RawMessage // MIME bundle, keyed by MIME type (e.g. "text/plain", "image/png")
+	ErrorName      string                     // Exception class name
+	ErrorValue     string                     // Exception message
+	ErrorTraceback []string                   // Formatted traceback lines
+}
+
+// Internal structures for parsing notebook cell results
+type (
+	cellData struct {
+		ExecutionCount int          `json:"execution_count"`
+		Status         string       `json:"status"`
+		Outputs        []cellOutput `json:"outputs"`
+	}
+
+	cellOutput struct {
+		OutputType string                     `json:"output_type"`
+		Name       string                     `json:"name,omitempty"`
+		Text       string                     `json:"text,omitempty"`
+		Data       map[string]json.RawMessage `json:"data,omitempty"`
+		EName      string                     `json:"ename,omitempty"`
+		EValue     string                     `json:"evalue,omitempty"`
+		Traceback  []string                   `json:"traceback,omitempty"`
+	}
+)
+
+func (cd cellData) toNotebookCell(source string) NotebookCell {
+	cell := NotebookCell{
+		Source:         source,
+		Outputs:        make([]CellOutput, 0, len(cd.Outputs)),
+		ExecutionCount: cd.ExecutionCount,
+		Status:         cd.Status,
+	}
+	for _, o := range cd.Outputs {
+		cell.Outputs = append(cell.Outputs, CellOutput{
+			Type:           CellOutputType(o.OutputType),
+			Name:           o.Name,
+			Text:           o.Text,
+			Data:           o.Data,
+			ErrorName:      o.EName,
+			ErrorValue:     o.EValue,
+			ErrorTraceback: o.Traceback,
+		})
+	}
+	return cell
+}
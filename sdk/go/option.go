@@ -4,8 +4,11 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
+	"time"
 )
 
 // Option configures a sandbox during creation.
@@ -32,7 +35,7 @@ func WithNamespace(namespace string) Option {
 // If not specified, a random name will be generated.
 func WithName(name string) Option {
 	return func(msb *baseMicroSandbox) {
-		msb.cfg.name = name
+		msb.cfg.setSandboxName(name)
 	}
 }
 
@@ -52,6 +55,15 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithMetricsCollector configures a collector that receives the duration and outcome of every RPC
+// call the SDK makes, so client-side telemetry can be exported to systems like Prometheus or StatsD.
+// If not specified, uses a no-op collector.
+func WithMetricsCollector(c Collector) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.collector = c
+	}
+}
+
 // WithReqIdProducer configures a custom request ID generator for tracing.
 // Request IDs are included in logs and can help with debugging.
 func WithReqIdProducer(reqIdPrd ReqIdProducer) Option {
@@ -60,6 +72,159 @@ func WithReqIdProducer(reqIdPrd ReqIdProducer) Option {
 	}
 }
 
+// WithEnv sets environment variables for the whole sandbox, seen by both the REPL kernel and commands.
+// Multiple calls are merged, with later values overriding earlier ones for the same key.
+func WithEnv(env map[string]string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.env = mergeEnv(msb.cfg.env, env)
+	}
+}
+
+// WithReplEnv sets environment variables seen only by the REPL kernel, not by Command().Run.
+// They are layered over the sandbox environment: the kernel starts from the variables set via
+// WithEnv, then applies these on top, so a key set in both takes its WithReplEnv value.
+// Multiple calls are merged like WithEnv.
+func WithReplEnv(env map[string]string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.replEnv = mergeEnv(msb.cfg.replEnv, env)
+	}
+}
+
+// WithLocale sets the locale of the sandbox (e.g. "en_US.UTF-8").
+// It is forwarded at Start as the LANG and LC_ALL environment variables.
+func WithLocale(locale string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.locale = locale
+	}
+}
+
+// WithTimezone sets the timezone of the sandbox as an IANA name (e.g. "Europe/Berlin").
+// It is forwarded at Start as the TZ environment variable; Start returns ErrInvalidTimezone for unknown names.
+// Names are checked against the host's timezone database; if the host has none, Start returns
+// ErrTimezoneDatabaseUnavailable, and the application can embed one by importing time/tzdata.
+func WithTimezone(tz string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.timezone = tz
+	}
+}
+
+// WithFakeTime makes code inside the sandbox see a clock that starts at t when the sandbox starts
+// and advances normally from there.
+//
+// Servers that do not know the setting ignore it, so after launch Start reads the clock inside the
+// sandbox and, if it is more than a minute off the requested time, stops the sandbox and returns
+// ErrFakeTimeUnsupported. A t within a minute of the real time therefore cannot be verified.
+//
+// The server implements this at library level by preloading libfaketime into sandbox processes,
+// which intercepts libc time calls (time, gettimeofday, clock_gettime, ...). It does not affect
+// statically linked binaries, programs that bypass libc to read the clock (e.g. Go binaries),
+// or kernel-generated timestamps such as file modification times.
+func WithFakeTime(t time.Time) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.fakeTime = &fakeTime{start: t}
+	}
+}
+
+// WithFrozenTime is like WithFakeTime, except the clock stays fixed at t instead of advancing.
+// The same mechanism and limitations apply.
+func WithFrozenTime(t time.Time) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.fakeTime = &fakeTime{start: t, frozen: true}
+	}
+}
+
+// WithDiagnosticsOnError makes failed Start (including via StartMany), Code().Run/RunCell and
+// Command().Run/Pipe/RunWithDeadline/RunTee/RunTeeContext/RunShellScript calls gather recent sandbox
+// logs, the process list and the last metrics, and attach them to the returned error as a
+// *DiagnosticError. Collection is best-effort and bounded to a few seconds. It is skipped when the
+// operation failed because its context was cancelled, so cancelling never waits on collection.
+func WithDiagnosticsOnError() Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.diagnosticsOnError = true
+	}
+}
+
+// WithUnreachableFallback registers fn to be called whenever an RPC fails because the server is unreachable,
+// giving a single place to implement degraded behaviour (serve cached data, queue the operation, ...).
+//
+// fn is invoked for transport-level failures, where no HTTP response was received (connection refused,
+// DNS resolution failure, timeout; matched by ErrSendRequestFailed), and for 502, 503 and 504
+// responses from a gateway in front of the server (matched by ErrServerUnavailable).
+// It is not invoked for JSON-RPC errors returned by a reachable server, other HTTP statuses,
+// certificate verification or pinning failures, or cancellation of the operation's context.
+//
+// The error fn returns is what the operation returns. If fn returns nil, the operation succeeds
+// with an empty result (e.g. a zero CodeExecution or Metrics). Lifecycle operations (Start, including
+// its start-up checks and seed uploads, Stop and Rename) cannot be suppressed, since the sandbox's
+// state would no longer match the server's: for them a nil return is ignored and the original error
+// is returned.
+func WithUnreachableFallback(fn UnreachableFallback) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.unreachableFallback = fn
+	}
+}
+
+// WithSecurityProfile applies the given capability and seccomp restrictions to the sandbox at Start.
+// Start returns ErrUnknownCapability for capability names it does not recognize. Since servers that
+// do not know the setting ignore it, Start then inspects a process inside the sandbox after launch,
+// and stops the sandbox and returns ErrSecurityProfileUnsupported unless every dropped capability
+// is gone from the bounding set and, if a seccomp policy was requested, a seccomp filter is active.
+// The effective profile of a sandbox is reported in SandboxInfo.
+func WithSecurityProfile(profile SecurityProfile) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.security = &profile
+	}
+}
+
+// WithConcurrencyLimit caps how many operations on the sandbox (code runs, commands, file operations,
+// metrics, ...) may be in flight at once, queueing the rest, to apply backpressure on a small sandbox
+// shared across goroutines. An operation holds its slot until it returns, so a RunTee, RunTeeContext,
+// RunWithDeadline or RunShellScript occupies one for as long as its command runs; background pollers
+// (Processes().Stream, AppLogs) take one per poll. Queued operations give up with
+// ErrConcurrencyLimitWait when their context is done or, since most methods have no context, after
+// waiting one minute (see WithConcurrencyWaitTimeout). Start (including its start-up checks and
+// seeding), Stop, Rename, diagnostics collection and the kill sent for an abandoned command are never
+// queued. If n <= 0, operations are not limited.
+func WithConcurrencyLimit(n int) Option {
+	return func(msb *baseMicroSandbox) {
+		if n > 0 {
+			msb.cfg.inFlight = make(chan struct{}, n)
+		} else {
+			msb.cfg.inFlight = nil
+		}
+	}
+}
+
+// WithConcurrencyWaitTimeout sets how long an operation queued by WithConcurrencyLimit waits for a slot
+// before failing with ErrConcurrencyLimitWait. If d <= 0, operations wait until their context is done.
+func WithConcurrencyWaitTimeout(d time.Duration) Option {
+	return func(msb *baseMicroSandbox) {
+		if d <= 0 {
+			d = -1
+		}
+		msb.cfg.inFlightWait = d
+	}
+}
+
+// WithAppLogFile sets the path, inside the sandbox, of the application log file tailed by AppLogs.
+func WithAppLogFile(path string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.appLogPath = path
+	}
+}
+
+// WithSeedDir uploads the contents of localDir into guestDir inside the sandbox as part of Start,
+// so the files are present before Start returns and any code can run. Start fails, and the sandbox
+// is stopped, if the upload fails. The option may be repeated to seed several directories, which
+// are uploaded in order. localDir itself may be a symlink to a directory; below it, only directories
+// and regular files are copied, and symlinks are skipped. Files are streamed in 1 MiB chunks, so
+// large files are never held in memory whole.
+func WithSeedDir(localDir, guestDir string) Option {
+	return func(msb *baseMicroSandbox) {
+		msb.cfg.seedDirs = append(msb.cfg.seedDirs, seedDir{local: localDir, guest: guestDir})
+	}
+}
+
 // WithHTTPClient configures a custom HTTP client for server communication.
 // Useful for setting timeouts, proxies, or other HTTP-level configuration.
 func WithHTTPClient(c *http.Client) Option {
@@ -68,6 +233,21 @@ func WithHTTPClient(c *http.Client) Option {
 	}
 }
 
+// WithPinnedCertFingerprint pins the server's TLS certificate to the given hex-encoded SHA-256
+// fingerprint (colon separators allowed). Connections to a server presenting any other leaf
+// certificate fail with ErrCertificatePinMismatch, even if the certificate is otherwise CA-valid.
+// Works with the default HTTP client and with clients set via WithHTTPClient that use an *http.Transport.
+// The server URL must use https; construction panics with ErrCertificatePinningRequiresHTTPS otherwise.
+func WithPinnedCertFingerprint(sha256hex string) Option {
+	return func(msb *baseMicroSandbox) {
+		fp, err := normalizeFingerprint(sha256hex)
+		if err != nil {
+			panic(err)
+		}
+		msb.cfg.certPin = fp
+	}
+}
+
 // --- internal constructor operations ---
 
 func fillDefaultConfigs() Option {
@@ -82,12 +262,15 @@ func fillDefaultConfigs() Option {
 		if msb.cfg.namespace == "" {
 			msb.cfg.namespace = defaultNamespace
 		}
-		if msb.cfg.name == "" {
+		if msb.cfg.sandboxName() == "" {
 			b := make([]byte, 4) // 4 bytes == 8 hex chars
 			if _, err := rand.Read(b); err != nil {
 				panic(fmt.Errorf("%w: %w", ErrFailedToGenerateRandomName, err))
 			}
-			msb.cfg.name = fmt.Sprintf(defaultNameTemplate, b)
+			msb.cfg.setSandboxName(fmt.Sprintf(defaultNameTemplate, b))
+		}
+		if msb.cfg.inFlightWait == 0 {
+			msb.cfg.inFlightWait = defaultConcurrencyWait
 		}
 		if msb.cfg.apiKey == "" {
 			if envApiKey := os.Getenv("MSB_API_KEY"); envApiKey != "" {
@@ -99,6 +282,14 @@ func fillDefaultConfigs() Option {
 	}
 }
 
+func mergeEnv(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
 func fillDefaultLogger() Option {
 	return func(msb *baseMicroSandbox) {
 		if msb.cfg.logger == nil {
@@ -107,6 +298,14 @@ func fillDefaultLogger() Option {
 	}
 }
 
+func fillDefaultCollector() Option {
+	return func(msb *baseMicroSandbox) {
+		if msb.cfg.collector == nil {
+			msb.cfg.collector = NoOpCollector{}
+		}
+	}
+}
+
 func fillDefaultRPCClient() Option {
 	return func(msb *baseMicroSandbox) {
 		if msb.rpcClient == nil {
@@ -115,9 +314,36 @@ func fillDefaultRPCClient() Option {
 	}
 }
 
+func fillCertPinning() Option {
+	return func(msb *baseMicroSandbox) {
+		if msb.cfg.certPin == "" {
+			return
+		}
+		// Pins are only checked during the TLS handshake, so a plain-HTTP URL would bypass them entirely
+		if u, err := url.Parse(msb.cfg.serverUrl); err != nil || u.Scheme != "https" {
+			panic(fmt.Errorf("%w: got %q", ErrCertificatePinningRequiresHTTPS, msb.cfg.serverUrl))
+		}
+		c, ok := msb.rpcClient.(*jsonRPCHTTPClient)
+		if !ok {
+			panic(ErrCertificatePinningUnsupported)
+		}
+		if c.pin == msb.cfg.certPin {
+			return
+		}
+		pinned, err := pinnedHTTPClient(c.Client, msb.cfg.certPin)
+		if err != nil {
+			panic(err)
+		}
+		msb.rpcClient = &jsonRPCHTTPClient{Client: pinned, pin: msb.cfg.certPin}
+	}
+}
+
 // Option-related errors
 var (
 	ErrLanguageMustBeSpecified    = errors.New("language must be specified")
 	ErrFailedToGenerateRandomName = errors.New("failed to generate random name")
 	ErrAPIKeyMustBeSpecified      = errors.New("API key must be specified either via WithApiKey() or MSB_API_KEY environment variable")
+	ErrInvalidTimezone            = errors.New("invalid timezone")
+
+	ErrTimezoneDatabaseUnavailable = errors.New("timezone database unavailable; import time/tzdata to embed one")
 )
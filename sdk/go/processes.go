@@ -0,0 +1,98 @@
+package msb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProcessReader provides access to the processes running inside the sandbox.
+type ProcessReader interface {
+	// List returns a one-shot snapshot of the processes running in the sandbox.
+	List() ([]ProcessInfo, error)
+	// Stream emits the full process list every interval, like a live top view, until ctx is
+	// cancelled, at which point the channel is closed. The first snapshot is emitted immediately.
+	// Snapshots that fail to load are logged and skipped; a slow receiver causes ticks to be dropped.
+	Stream(ctx context.Context, interval time.Duration) (<-chan []ProcessInfo, error)
+}
+
+// ProcessInfo describes a process running inside the sandbox.
+type ProcessInfo struct {
+	PID       int     // Process ID
+	PPID      int     // Parent process ID
+	User      string  // Owning user
+	Command   string  // Command line
+	CPU       float64 // CPU usage percentage (0-100)
+	MemoryMiB int     // Resident memory in mebibytes
+}
+
+type processReader struct {
+	b *baseMicroSandbox
+}
+
+func (pr processReader) List() ([]ProcessInfo, error) {
+	if pr.b.state.Load() != started {
+		return nil, ErrSandboxNotStarted
+	}
+	return pr.list(context.Background())
+}
+
+func (pr processReader) Stream(ctx context.Context, interval time.Duration) (<-chan []ProcessInfo, error) {
+	if pr.b.state.Load() != started {
+		return nil, ErrSandboxNotStarted
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInterval, interval)
+	}
+
+	ch := make(chan []ProcessInfo)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if procs, err := pr.list(ctx); err == nil {
+				select {
+				case ch <- procs:
+				case <-ctx.Done():
+					return
+				}
+			} else if ctx.Err() == nil {
+				pr.b.cfg.logger.Error("Failed to list processes", "sandbox", pr.b.cfg.sandboxName(), "error", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (pr processReader) list(ctx context.Context) ([]ProcessInfo, error) {
+	entries, err := pr.b.rpcClient.listProcesses(ctx, &pr.b.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListProcesses, err)
+	}
+
+	procs := make([]ProcessInfo, 0, len(entries))
+	for _, e := range entries {
+		procs = append(procs, ProcessInfo{
+			PID:       e.PID,
+			PPID:      e.PPID,
+			User:      e.User,
+			Command:   e.Command,
+			CPU:       e.CPUUsage,
+			MemoryMiB: e.MemoryUsage,
+		})
+	}
+	return procs, nil
+}
+
+// Process-related errors
+var (
+	ErrFailedToListProcesses = errors.New("failed to list processes")
+	ErrInvalidInterval       = errors.New("interval must be positive")
+)
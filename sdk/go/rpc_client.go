@@ -3,10 +3,12 @@ package msb
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"time"
 )
@@ -15,9 +17,26 @@ import (
 type rpcClient interface {
 	startSandbox(ctx context.Context, cfg *config, image string, memory int, cpus int) error
 	stopSandbox(ctx context.Context, cfg *config) error
+	renameSandbox(ctx context.Context, cfg *config, newName string) error
 	runRepl(ctx context.Context, cfg *config, lang progLang, code string) (*executionResult, error)
+	flushRepl(ctx context.Context, cfg *config, lang progLang) (*executionResult, error)
+	runReplCell(ctx context.Context, cfg *config, lang progLang, code string) (*executionResult, error)
 	runCommand(ctx context.Context, cfg *config, command string, args []string) (*executionResult, error)
+	startCommand(ctx context.Context, cfg *config, command string, args []string) (string, error)
+	getCommandOutput(ctx context.Context, cfg *config, executionID string, offset int) (*commandOutputChunk, error)
+	runCommandWithDeadline(ctx context.Context, cfg *config, executionID string, command string, args []string, deadline time.Duration, killGroup bool) (*executionResult, error)
+	killCommand(ctx context.Context, cfg *config, executionID string, killGroup bool) error
+	runPipeline(ctx context.Context, cfg *config, stages []Command) (*executionResult, error)
 	getMetrics(ctx context.Context, cfg *config) (*sandboxMetrics, error)
+	getLogs(ctx context.Context, cfg *config, tail int) ([]string, error)
+	getLimits(ctx context.Context, cfg *config) (*sandboxLimits, error)
+	getKernelInfo(ctx context.Context, cfg *config) (*kernelInfoResult, error)
+	listProcesses(ctx context.Context, cfg *config) ([]processEntry, error)
+	listSandboxes(ctx context.Context, cfg *config, cursor string, limit int) (*sandboxListResult, error)
+	listCrashArtifacts(ctx context.Context, cfg *config) ([]fileEntry, error)
+	getCrashArtifactChunk(ctx context.Context, cfg *config, path string, offset int64, length int) (*crashGetResult, error)
+	tailFile(ctx context.Context, cfg *config, path string, offset int64) (*fileTailResult, error)
+	writeFile(ctx context.Context, cfg *config, path string, offset int64, data []byte, perm fs.FileMode) error
 }
 
 // rpcMethod represents a JSON-RPC method name
@@ -25,11 +44,27 @@ type rpcMethod string
 
 // JSON-RPC method constants
 const (
-	methodSandboxStart      rpcMethod = "sandbox.start"
-	methodSandboxStop       rpcMethod = "sandbox.stop"
-	methodSandboxReplRun    rpcMethod = "sandbox.repl.run"
-	methodSandboxCommandRun rpcMethod = "sandbox.command.run"
-	methodSandboxMetricsGet rpcMethod = "sandbox.metrics.get"
+	methodSandboxStart            rpcMethod = "sandbox.start"
+	methodSandboxStop             rpcMethod = "sandbox.stop"
+	methodSandboxRename           rpcMethod = "sandbox.rename"
+	methodSandboxReplRun          rpcMethod = "sandbox.repl.run"
+	methodSandboxReplRunCell      rpcMethod = "sandbox.repl.run_cell"
+	methodSandboxReplFlush        rpcMethod = "sandbox.repl.flush"
+	methodSandboxCommandRun       rpcMethod = "sandbox.command.run"
+	methodSandboxCommandPipe      rpcMethod = "sandbox.command.pipe"
+	methodSandboxCommandStart     rpcMethod = "sandbox.command.start"
+	methodSandboxCommandGetOutput rpcMethod = "sandbox.command.get_output"
+	methodSandboxCommandKill      rpcMethod = "sandbox.command.kill"
+	methodSandboxMetricsGet       rpcMethod = "sandbox.metrics.get"
+	methodSandboxLimitsGet        rpcMethod = "sandbox.limits.get"
+	methodSandboxKernelInfo       rpcMethod = "sandbox.kernel.info"
+	methodSandboxLogsGet          rpcMethod = "sandbox.logs.get"
+	methodSandboxList             rpcMethod = "sandbox.list"
+	methodSandboxProcessList      rpcMethod = "sandbox.processes.list"
+	methodSandboxCrashList        rpcMethod = "sandbox.crash.list"
+	methodSandboxCrashGet         rpcMethod = "sandbox.crash.get"
+	methodSandboxFileWrite        rpcMethod = "sandbox.files.write"
+	methodSandboxFileTail         rpcMethod = "sandbox.files.tail"
 )
 
 // endpoint routing path
@@ -64,9 +99,18 @@ type startParams struct {
 }
 
 type startConfig struct {
-	Image  string `json:"image"`
-	Memory int    `json:"memory"`
-	CPUs   int    `json:"cpus"`
+	Image    string                 `json:"image"`
+	Memory   int                    `json:"memory"`
+	CPUs     int                    `json:"cpus"`
+	Envs     []string               `json:"envs,omitempty"`
+	ReplEnvs []string               `json:"repl_envs,omitempty"`
+	FakeTime *fakeTimeConfig        `json:"fake_time,omitempty"`
+	Security *securityProfileConfig `json:"security_profile,omitempty"`
+}
+
+type fakeTimeConfig struct {
+	Start  string `json:"start"` // RFC 3339
+	Frozen bool   `json:"frozen"`
 }
 
 type stopParams struct {
@@ -74,6 +118,12 @@ type stopParams struct {
 	Sandbox   string `json:"sandbox"`
 }
 
+type renameParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	NewName   string `json:"new_name"`
+}
+
 type replRunParams struct {
 	Namespace string `json:"namespace"`
 	Sandbox   string `json:"sandbox"`
@@ -81,12 +131,48 @@ type replRunParams struct {
 	Code      string `json:"code"`
 }
 
+type replFlushParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	Language  string `json:"language"`
+}
+
 type commandRunParams struct {
 	Namespace string   `json:"namespace"`
 	Sandbox   string   `json:"sandbox"`
 	Command   string   `json:"command"`
 	Args      []string `json:"args"`
 	Timeout   int      `json:"timeout,omitempty"`
+
+	ExecutionID      string `json:"execution_id,omitempty"`       // client-chosen ID, so the command can be killed while running
+	DeadlineMs       int64  `json:"deadline_ms,omitempty"`        // server-side deadline, relative to receipt
+	KillProcessGroup bool   `json:"kill_process_group,omitempty"` // kill the whole process group on deadline
+}
+
+type commandKillParams struct {
+	Namespace        string `json:"namespace"`
+	Sandbox          string `json:"sandbox"`
+	ExecutionID      string `json:"execution_id"`
+	KillProcessGroup bool   `json:"kill_process_group,omitempty"`
+}
+
+type commandGetOutputParams struct {
+	Namespace   string `json:"namespace"`
+	Sandbox     string `json:"sandbox"`
+	ExecutionID string `json:"execution_id"`
+	Offset      int    `json:"offset"` // number of output lines already received
+}
+
+type commandPipeParams struct {
+	Namespace string          `json:"namespace"`
+	Sandbox   string          `json:"sandbox"`
+	Stages    []pipelineStage `json:"stages"`
+	Timeout   int             `json:"timeout,omitempty"`
+}
+
+type pipelineStage struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
 }
 
 type metricsGetParams struct {
@@ -94,11 +180,82 @@ type metricsGetParams struct {
 	SandboxName string `json:"sandbox"`
 }
 
+type logsGetParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	Tail      int    `json:"tail"`
+}
+
+type limitsGetParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+}
+
+type processListParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+}
+
+type sandboxListParams struct {
+	Namespace string `json:"namespace"`
+	Cursor    string `json:"cursor,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+type kernelInfoParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+}
+
+type crashListParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+}
+
+type crashGetParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	Path      string `json:"path"`
+	Offset    int64  `json:"offset"`
+	Length    int    `json:"length"`
+}
+
+type fileWriteParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	Path      string `json:"path"`
+	Content   []byte `json:"content"` // base64-encoded on the wire
+	Mode      uint32 `json:"mode"`
+	Offset    int64  `json:"offset,omitempty"` // 0 creates or truncates the file; otherwise writes at this byte offset
+}
+
+type fileTailParams struct {
+	Namespace string `json:"namespace"`
+	Sandbox   string `json:"sandbox"`
+	Path      string `json:"path"`
+	Offset    int64  `json:"offset"` // byte offset to resume from, or tailFromEnd
+}
+
+// tailFromEnd asks sandbox.files.tail for no lines, only the current end offset of the file.
+const tailFromEnd int64 = -1
+
 // Response types
 type executionResult struct {
 	output json.RawMessage `json:"-"` // Store raw JSON for flexible parsing
 }
 
+type commandStartResult struct {
+	ExecutionID string `json:"execution_id"`
+}
+
+// commandOutputChunk holds the output lines produced since the requested offset.
+// Once Done, Result holds the full execution result, as returned by sandbox.command.run.
+type commandOutputChunk struct {
+	Lines  []outputLine    `json:"output"`
+	Done   bool            `json:"done"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
 type metricsResult struct {
 	Sandboxes []sandboxMetrics `json:"sandboxes"`
 }
@@ -112,10 +269,149 @@ type sandboxMetrics struct {
 	DiskUsage   int     `json:"disk_usage"`
 }
 
+type sandboxLimits struct {
+	MemoryLimit int                    `json:"memory_limit"` // MiB, -1 if unlimited
+	CPULimit    float64                `json:"cpu_limit"`    // cores, -1 if unlimited
+	DiskLimit   int                    `json:"disk_limit"`   // bytes, -1 if unlimited
+	Ulimits     map[string]ulimitValue `json:"ulimits"`
+}
+
+type ulimitValue struct {
+	Soft int64 `json:"soft"`
+	Hard int64 `json:"hard"`
+}
+
+type processListResult struct {
+	Processes []processEntry `json:"processes"`
+}
+
+type processEntry struct {
+	PID         int     `json:"pid"`
+	PPID        int     `json:"ppid"`
+	User        string  `json:"user"`
+	Command     string  `json:"command"`
+	CPUUsage    float64 `json:"cpu_usage"`
+	MemoryUsage int     `json:"memory_usage"`
+}
+
+type kernelInfoResult struct {
+	KernelVersion string `json:"kernel_version"`
+	Arch          string `json:"arch"`
+	VMMVersion    string `json:"vmm_version"`
+}
+
+type sandboxListResult struct {
+	Sandboxes  []sandboxEntry `json:"sandboxes"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+type sandboxEntry struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Image     string                 `json:"image"`
+	Memory    int                    `json:"memory"`
+	CPUs      int                    `json:"cpus"`
+	Running   bool                   `json:"running"`
+	Security  *securityProfileConfig `json:"security_profile"`
+}
+
+func (s sandboxEntry) toSandboxInfo() SandboxInfo {
+	return SandboxInfo{
+		Name:      s.Name,
+		Namespace: s.Namespace,
+		Image:     s.Image,
+		MemoryMiB: s.Memory,
+		CPUs:      s.CPUs,
+		IsRunning: s.Running,
+		Security:  s.Security.toSecurityProfile(),
+	}
+}
+
+type crashListResult struct {
+	Files []fileEntry `json:"files"`
+}
+
+type fileEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Modified int64  `json:"modified"` // Unix seconds
+}
+
+func (f fileEntry) toFileInfo() FileInfo {
+	return FileInfo{
+		Name:    f.Name,
+		Path:    f.Path,
+		Size:    f.Size,
+		ModTime: time.Unix(f.Modified, 0),
+	}
+}
+
+// fileTailResult holds the complete lines appended since the requested offset,
+// and the offset to resume from on the next call.
+type fileTailResult struct {
+	Lines  []string `json:"lines"`
+	Offset int64    `json:"offset"`
+}
+
+// crashGetResult is one chunk of a crash artifact, starting at the requested offset.
+// EOF is set once the chunk reaches the end of the artifact.
+type crashGetResult struct {
+	Content []byte `json:"content"` // base64-encoded on the wire
+	EOF     bool   `json:"eof"`
+}
+
+// rpcCallError is a JSON-RPC error returned by the server. It matches ErrRPCCall via errors.Is
+// and keeps the error code so callers can map specific server failures to SDK errors.
+type rpcCallError struct {
+	code    int
+	message string
+}
+
+func (e *rpcCallError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRPCCall, e.message)
+}
+
+func (e *rpcCallError) Unwrap() error {
+	return ErrRPCCall
+}
+
+// Standard JSON-RPC error code for a method the server does not implement
+const rpcCodeMethodNotFound = -32601
+
+// Application-defined JSON-RPC error codes returned by the server
+const (
+	rpcCodeFakeTimeUnsupported        = -32010
+	rpcCodeSecurityProfileUnsupported = -32011
+	rpcCodeSandboxNameTaken           = -32012
+)
+
+// hasRPCCode reports whether err carries a JSON-RPC error with the given code.
+func hasRPCCode(err error, code int) bool {
+	var rpcErr *rpcCallError
+	return errors.As(err, &rpcErr) && rpcErr.code == code
+}
+
+func (m sandboxMetrics) toMetrics() Metrics {
+	return Metrics{
+		Name:      m.Name,
+		Namespace: m.Namespace,
+		IsRunning: m.Running,
+		CPU:       m.CPUUsage,
+		MemoryMiB: m.MemoryUsage,
+		DiskBytes: m.DiskUsage,
+	}
+}
+
+type logsGetResult struct {
+	Lines []string `json:"lines"`
+}
+
 var _ rpcClient = &jsonRPCHTTPClient{}
 
 type jsonRPCHTTPClient struct {
 	*http.Client
+	pin string // Certificate fingerprint enforced by the client's transport, empty if none
 }
 
 func newDefaultJsonRPCHTTPClient() rpcClient {
@@ -131,7 +427,74 @@ func newDefaultJsonRPCHTTPClient() rpcClient {
 }
 
 func newJsonRPCHTTPClient(c *http.Client) rpcClient {
-	return &jsonRPCHTTPClient{c}
+	return &jsonRPCHTTPClient{Client: c}
+}
+
+// call issues a JSON-RPC request on behalf of the sandbox described by cfg.
+func (d *jsonRPCHTTPClient) call(ctx context.Context, cfg *config, method rpcMethod, params any) (jsonRPCResponse, error) {
+	if !bypassesConcurrencyLimit(method) {
+		var release func()
+		var err error
+		if ctx, release, err = cfg.acquireSlot(ctx, string(method)); err != nil {
+			return jsonRPCResponse{}, err
+		}
+		defer release()
+	}
+
+	start := time.Now()
+	resp, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, method, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.collector.ObserveRPCDuration(string(method), time.Since(start))
+	if err != nil {
+		cfg.collector.IncRPCError(string(method))
+	}
+	if err != nil && cfg.unreachableFallback != nil && isUnreachable(err) {
+		cfg.logger.Debug("Server unreachable, invoking fallback", "method", string(method), "error", err)
+		fallbackErr := cfg.unreachableFallback(string(method), err)
+		switch {
+		case fallbackErr != nil:
+			err = fallbackErr
+		case isLifecycleMethod(method) || isLifecycleCall(ctx):
+			cfg.logger.Debug("Ignoring fallback suppression of lifecycle operation", "method", string(method))
+		default:
+			// Suppressed: the operation proceeds as if it succeeded with an empty result
+			return jsonRPCResponse{Result: json.RawMessage("null")}, nil
+		}
+	}
+	return resp, err
+}
+
+// isLifecycleMethod reports whether method changes the sandbox's lifecycle state or identity,
+// so its failure must never be reported as success.
+func isLifecycleMethod(method rpcMethod) bool {
+	switch method {
+	case methodSandboxStart, methodSandboxStop, methodSandboxRename:
+		return true
+	}
+	return false
+}
+
+// lifecycleCallKey marks a context whose RPCs are part of a lifecycle operation, such as the
+// verification checks and seed uploads run by Start, and so must not be suppressed either.
+type lifecycleCallKey struct{}
+
+func withLifecycleCall(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lifecycleCallKey{}, true)
+}
+
+func isLifecycleCall(ctx context.Context) bool {
+	lifecycle, _ := ctx.Value(lifecycleCallKey{}).(bool)
+	return lifecycle
+}
+
+// isUnreachable reports whether err means the server could not be reached: the request was never
+// answered (connection refused, DNS failure, timeout, ...) or a gateway reported the server unavailable.
+// Cancellation by the caller and certificate verification failures are not considered unreachability.
+func isUnreachable(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrCertificatePinMismatch) || errors.As(err, &certErr) {
+		return false
+	}
+	return errors.Is(err, ErrSendRequestFailed) || errors.Is(err, ErrServerUnavailable)
 }
 
 func (d *jsonRPCHTTPClient) makeJSONRPCRequest(ctx context.Context, serverURL string, method rpcMethod, params any, apiKey string, logger Logger, reqIdPrd ReqIdProducer) (resp jsonRPCResponse, err error) {
@@ -177,6 +540,15 @@ func (d *jsonRPCHTTPClient) makeJSONRPCRequest(ctx context.Context, serverURL st
 	if httpResp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(httpResp.Body)
 		logger.Error("HTTP request failed", "method", string(method), "status", httpResp.StatusCode, "body", string(body))
+		switch httpResp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return resp, fmt.Errorf("%w: %w: status %d: %s", ErrRequestFailed, ErrServerUnavailable, httpResp.StatusCode, string(body))
+		}
+		// The server reports unknown methods as a JSON-RPC error in a 404 response
+		var errResp jsonRPCResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil && errResp.Error.Code == rpcCodeMethodNotFound {
+			return resp, fmt.Errorf("%w: %s: %w", ErrMethodUnsupported, method, &rpcCallError{code: errResp.Error.Code, message: errResp.Error.Message})
+		}
 		return resp, fmt.Errorf("%w: status %d: %s", ErrRequestFailed, httpResp.StatusCode, string(body))
 	}
 
@@ -192,7 +564,11 @@ func (d *jsonRPCHTTPClient) makeJSONRPCRequest(ctx context.Context, serverURL st
 
 	if jsonResp.Error != nil {
 		logger.Error("JSON-RPC error", "method", string(method), "error", jsonResp.Error.Message, "code", jsonResp.Error.Code)
-		return resp, fmt.Errorf("%w: %s", ErrRPCCall, jsonResp.Error.Message)
+		callErr := &rpcCallError{code: jsonResp.Error.Code, message: jsonResp.Error.Message}
+		if callErr.code == rpcCodeMethodNotFound {
+			return resp, fmt.Errorf("%w: %s: %w", ErrMethodUnsupported, method, callErr)
+		}
+		return resp, callErr
 	}
 
 	logger.Debug("JSON-RPC request completed successfully", "method", string(method), "id", req.ID)
@@ -202,18 +578,29 @@ func (d *jsonRPCHTTPClient) makeJSONRPCRequest(ctx context.Context, serverURL st
 func (d *jsonRPCHTTPClient) startSandbox(ctx context.Context, cfg *config, image string, memory int, cpus int) error {
 	params := startParams{
 		Namespace: cfg.namespace,
-		Sandbox:   cfg.name,
+		Sandbox:   cfg.sandboxName(),
 		Config: startConfig{
-			Image:  image,
-			Memory: memory,
-			CPUs:   cpus,
+			Image:    image,
+			Memory:   memory,
+			CPUs:     cpus,
+			Envs:     cfg.startEnvs(),
+			ReplEnvs: envList(cfg.replEnv),
 		},
 	}
+	if cfg.security != nil {
+		params.Config.Security = cfg.security.toConfig()
+	}
+	if cfg.fakeTime != nil {
+		params.Config.FakeTime = &fakeTimeConfig{
+			Start:  cfg.fakeTime.start.Format(time.RFC3339Nano),
+			Frozen: cfg.fakeTime.frozen,
+		}
+	}
 
-	cfg.logger.Info("Starting sandbox", "name", cfg.name, "namespace", cfg.namespace, "image", image, "memory", memory, "cpus", cpus)
-	_, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, methodSandboxStart, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.logger.Info("Starting sandbox", "name", cfg.sandboxName(), "namespace", cfg.namespace, "image", image, "memory", memory, "cpus", cpus)
+	_, err := d.call(ctx, cfg, methodSandboxStart, params)
 	if err == nil {
-		cfg.logger.Info("Sandbox started successfully", "name", cfg.name)
+		cfg.logger.Info("Sandbox started successfully", "name", cfg.sandboxName())
 	}
 	return err
 }
@@ -221,13 +608,28 @@ func (d *jsonRPCHTTPClient) startSandbox(ctx context.Context, cfg *config, image
 func (d *jsonRPCHTTPClient) stopSandbox(ctx context.Context, cfg *config) error {
 	params := stopParams{
 		Namespace: cfg.namespace,
-		Sandbox:   cfg.name,
+		Sandbox:   cfg.sandboxName(),
 	}
 
-	cfg.logger.Info("Stopping sandbox", "name", cfg.name, "namespace", cfg.namespace)
-	_, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, methodSandboxStop, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.logger.Info("Stopping sandbox", "name", cfg.sandboxName(), "namespace", cfg.namespace)
+	_, err := d.call(ctx, cfg, methodSandboxStop, params)
 	if err == nil {
-		cfg.logger.Info("Sandbox stopped successfully", "name", cfg.name)
+		cfg.logger.Info("Sandbox stopped successfully", "name", cfg.sandboxName())
+	}
+	return err
+}
+
+func (d *jsonRPCHTTPClient) renameSandbox(ctx context.Context, cfg *config, newName string) error {
+	params := renameParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		NewName:   newName,
+	}
+
+	cfg.logger.Info("Renaming sandbox", "name", cfg.sandboxName(), "namespace", cfg.namespace, "new_name", newName)
+	_, err := d.call(ctx, cfg, methodSandboxRename, params)
+	if err == nil {
+		cfg.logger.Info("Sandbox renamed successfully", "name", newName)
 	}
 	return err
 }
@@ -235,13 +637,46 @@ func (d *jsonRPCHTTPClient) stopSandbox(ctx context.Context, cfg *config) error
 func (d *jsonRPCHTTPClient) runRepl(ctx context.Context, cfg *config, lang progLang, code string) (*executionResult, error) {
 	params := replRunParams{
 		Namespace: cfg.namespace,
-		Sandbox:   cfg.name,
+		Sandbox:   cfg.sandboxName(),
 		Language:  lang.String(),
 		Code:      code,
 	}
 
-	cfg.logger.Debug("Executing code in REPL", "sandbox", cfg.name, "language", lang.String())
-	resp, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, methodSandboxReplRun, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.logger.Debug("Executing code in REPL", "sandbox", cfg.sandboxName(), "language", lang.String())
+	resp, err := d.call(ctx, cfg, methodSandboxReplRun, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executionResult{output: resp.Result}, nil
+}
+
+func (d *jsonRPCHTTPClient) flushRepl(ctx context.Context, cfg *config, lang progLang) (*executionResult, error) {
+	params := replFlushParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Language:  lang.String(),
+	}
+
+	cfg.logger.Debug("Flushing REPL output", "sandbox", cfg.sandboxName(), "language", lang.String())
+	resp, err := d.call(ctx, cfg, methodSandboxReplFlush, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executionResult{output: resp.Result}, nil
+}
+
+func (d *jsonRPCHTTPClient) runReplCell(ctx context.Context, cfg *config, lang progLang, code string) (*executionResult, error) {
+	params := replRunParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Language:  lang.String(),
+		Code:      code,
+	}
+
+	cfg.logger.Debug("Executing notebook cell in REPL", "sandbox", cfg.sandboxName(), "language", lang.String())
+	resp, err := d.call(ctx, cfg, methodSandboxReplRunCell, params)
 	if err != nil {
 		return nil, err
 	}
@@ -252,14 +687,116 @@ func (d *jsonRPCHTTPClient) runRepl(ctx context.Context, cfg *config, lang progL
 func (d *jsonRPCHTTPClient) runCommand(ctx context.Context, cfg *config, command string, args []string) (*executionResult, error) {
 	params := commandRunParams{
 		Namespace: cfg.namespace,
-		Sandbox:   cfg.name,
+		Sandbox:   cfg.sandboxName(),
 		Command:   command,
 		Args:      args,
 		Timeout:   int(d.Timeout),
 	}
 
-	cfg.logger.Debug("Executing command", "sandbox", cfg.name, "command", command, "args", args)
-	resp, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, methodSandboxCommandRun, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.logger.Debug("Executing command", "sandbox", cfg.sandboxName(), "command", command, "args", args)
+	resp, err := d.call(ctx, cfg, methodSandboxCommandRun, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executionResult{output: resp.Result}, nil
+}
+
+func (d *jsonRPCHTTPClient) startCommand(ctx context.Context, cfg *config, command string, args []string) (string, error) {
+	params := commandRunParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Command:   command,
+		Args:      args,
+		Timeout:   int(d.Timeout),
+	}
+
+	cfg.logger.Debug("Starting command", "sandbox", cfg.sandboxName(), "command", command, "args", args)
+	resp, err := d.call(ctx, cfg, methodSandboxCommandStart, params)
+	if err != nil {
+		return "", err
+	}
+
+	var result commandStartResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal command start result", "error", err)
+		return "", fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return result.ExecutionID, nil
+}
+
+func (d *jsonRPCHTTPClient) getCommandOutput(ctx context.Context, cfg *config, executionID string, offset int) (*commandOutputChunk, error) {
+	params := commandGetOutputParams{
+		Namespace:   cfg.namespace,
+		Sandbox:     cfg.sandboxName(),
+		ExecutionID: executionID,
+		Offset:      offset,
+	}
+
+	resp, err := d.call(ctx, cfg, methodSandboxCommandGetOutput, params)
+	if err != nil {
+		return nil, err
+	}
+	if string(resp.Result) == "null" {
+		// Empty result (e.g. suppressed by an unreachable fallback): stop polling
+		return &commandOutputChunk{Done: true}, nil
+	}
+
+	var result commandOutputChunk
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal command output result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) runCommandWithDeadline(ctx context.Context, cfg *config, executionID string, command string, args []string, deadline time.Duration, killGroup bool) (*executionResult, error) {
+	params := commandRunParams{
+		Namespace:        cfg.namespace,
+		Sandbox:          cfg.sandboxName(),
+		Command:          command,
+		Args:             args,
+		Timeout:          int(d.Timeout),
+		ExecutionID:      executionID,
+		DeadlineMs:       deadline.Milliseconds(),
+		KillProcessGroup: killGroup,
+	}
+
+	cfg.logger.Debug("Executing command with deadline", "sandbox", cfg.sandboxName(), "command", command, "args", args, "deadline", deadline, "kill_process_group", killGroup)
+	resp, err := d.call(ctx, cfg, methodSandboxCommandRun, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &executionResult{output: resp.Result}, nil
+}
+
+func (d *jsonRPCHTTPClient) killCommand(ctx context.Context, cfg *config, executionID string, killGroup bool) error {
+	params := commandKillParams{
+		Namespace:        cfg.namespace,
+		Sandbox:          cfg.sandboxName(),
+		ExecutionID:      executionID,
+		KillProcessGroup: killGroup,
+	}
+
+	cfg.logger.Debug("Killing command", "sandbox", cfg.sandboxName(), "execution_id", executionID, "kill_process_group", killGroup)
+	_, err := d.call(ctx, cfg, methodSandboxCommandKill, params)
+	return err
+}
+
+func (d *jsonRPCHTTPClient) runPipeline(ctx context.Context, cfg *config, stages []Command) (*executionResult, error) {
+	params := commandPipeParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Stages:    make([]pipelineStage, 0, len(stages)),
+		Timeout:   int(d.Timeout),
+	}
+	for _, s := range stages {
+		params.Stages = append(params.Stages, pipelineStage{Command: s.Cmd, Args: s.Args})
+	}
+
+	cfg.logger.Debug("Executing pipeline", "sandbox", cfg.sandboxName(), "stages", len(stages))
+	resp, err := d.call(ctx, cfg, methodSandboxCommandPipe, params)
 	if err != nil {
 		return nil, err
 	}
@@ -270,11 +807,11 @@ func (d *jsonRPCHTTPClient) runCommand(ctx context.Context, cfg *config, command
 func (d *jsonRPCHTTPClient) getMetrics(ctx context.Context, cfg *config) (*sandboxMetrics, error) {
 	params := metricsGetParams{
 		Namespace:   cfg.namespace,
-		SandboxName: cfg.name,
+		SandboxName: cfg.sandboxName(),
 	}
 
-	cfg.logger.Debug("Getting sandbox metrics", "sandbox", cfg.name)
-	resp, err := d.makeJSONRPCRequest(ctx, cfg.serverUrl, methodSandboxMetricsGet, params, cfg.apiKey, cfg.logger, cfg.reqIDPrd)
+	cfg.logger.Debug("Getting sandbox metrics", "sandbox", cfg.sandboxName())
+	resp, err := d.call(ctx, cfg, methodSandboxMetricsGet, params)
 	if err != nil {
 		return nil, err
 	}
@@ -293,6 +830,187 @@ func (d *jsonRPCHTTPClient) getMetrics(ctx context.Context, cfg *config) (*sandb
 	return &result.Sandboxes[0], nil
 }
 
+func (d *jsonRPCHTTPClient) getLogs(ctx context.Context, cfg *config, tail int) ([]string, error) {
+	params := logsGetParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Tail:      tail,
+	}
+
+	cfg.logger.Debug("Getting sandbox logs", "sandbox", cfg.sandboxName(), "tail", tail)
+	resp, err := d.call(ctx, cfg, methodSandboxLogsGet, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result logsGetResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal logs result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return result.Lines, nil
+}
+
+func (d *jsonRPCHTTPClient) getLimits(ctx context.Context, cfg *config) (*sandboxLimits, error) {
+	params := limitsGetParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+	}
+
+	cfg.logger.Debug("Getting sandbox limits", "sandbox", cfg.sandboxName())
+	resp, err := d.call(ctx, cfg, methodSandboxLimitsGet, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sandboxLimits
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal limits result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) listProcesses(ctx context.Context, cfg *config) ([]processEntry, error) {
+	params := processListParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+	}
+
+	cfg.logger.Debug("Listing processes", "sandbox", cfg.sandboxName())
+	resp, err := d.call(ctx, cfg, methodSandboxProcessList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result processListResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal process list result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return result.Processes, nil
+}
+
+func (d *jsonRPCHTTPClient) getKernelInfo(ctx context.Context, cfg *config) (*kernelInfoResult, error) {
+	params := kernelInfoParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+	}
+
+	cfg.logger.Debug("Getting kernel info", "sandbox", cfg.sandboxName())
+	resp, err := d.call(ctx, cfg, methodSandboxKernelInfo, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result kernelInfoResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal kernel info result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) listSandboxes(ctx context.Context, cfg *config, cursor string, limit int) (*sandboxListResult, error) {
+	params := sandboxListParams{
+		Namespace: cfg.namespace,
+		Cursor:    cursor,
+		Limit:     limit,
+	}
+
+	cfg.logger.Debug("Listing sandboxes", "namespace", cfg.namespace, "cursor", cursor, "limit", limit)
+	resp, err := d.call(ctx, cfg, methodSandboxList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sandboxListResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal sandbox list result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) listCrashArtifacts(ctx context.Context, cfg *config) ([]fileEntry, error) {
+	params := crashListParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+	}
+
+	cfg.logger.Debug("Listing crash artifacts", "sandbox", cfg.sandboxName())
+	resp, err := d.call(ctx, cfg, methodSandboxCrashList, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result crashListResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal crash list result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return result.Files, nil
+}
+
+func (d *jsonRPCHTTPClient) getCrashArtifactChunk(ctx context.Context, cfg *config, path string, offset int64, length int) (*crashGetResult, error) {
+	params := crashGetParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Path:      path,
+		Offset:    offset,
+		Length:    length,
+	}
+
+	cfg.logger.Debug("Downloading crash artifact chunk", "sandbox", cfg.sandboxName(), "path", path, "offset", offset)
+	resp, err := d.call(ctx, cfg, methodSandboxCrashGet, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result crashGetResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal crash artifact result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) tailFile(ctx context.Context, cfg *config, path string, offset int64) (*fileTailResult, error) {
+	params := fileTailParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Path:      path,
+		Offset:    offset,
+	}
+
+	resp, err := d.call(ctx, cfg, methodSandboxFileTail, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := fileTailResult{Offset: offset}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		cfg.logger.Error("Failed to unmarshal file tail result", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalRespFailed, err)
+	}
+	return &result, nil
+}
+
+func (d *jsonRPCHTTPClient) writeFile(ctx context.Context, cfg *config, path string, offset int64, data []byte, perm fs.FileMode) error {
+	params := fileWriteParams{
+		Namespace: cfg.namespace,
+		Sandbox:   cfg.sandboxName(),
+		Path:      path,
+		Content:   data,
+		Mode:      uint32(perm.Perm()),
+		Offset:    offset,
+	}
+
+	cfg.logger.Debug("Writing file", "sandbox", cfg.sandboxName(), "path", path, "offset", offset, "bytes", len(data))
+	_, err := d.call(ctx, cfg, methodSandboxFileWrite, params)
+	return err
+}
+
 // --- Error definitions ---
 var (
 	ErrMarshalReqFailed        = errors.New("failed to marshal request")
@@ -303,5 +1021,8 @@ var (
 	ErrUnmarshalRespFailed     = errors.New("failed to unmarshal response")
 	ErrUnmarshalMetricsFailed  = errors.New("failed to unmarshal metrics result")
 	ErrRequestFailed           = errors.New("request failed")
+	ErrServerUnavailable       = errors.New("server unavailable")
+	ErrConcurrencyLimitWait    = errors.New("gave up waiting for concurrency slot")
+	ErrMethodUnsupported       = errors.New("method not supported by server")
 	ErrRPCCall                 = errors.New("RPC error")
 )
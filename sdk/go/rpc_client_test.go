@@ -0,0 +1,40 @@
+package msb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestIsUnreachable(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	certErr := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: fmt.Errorf("%w: %w", ErrSendRequestFailed, refused), want: true},
+		{name: "request timeout", err: fmt.Errorf("%w: %w", ErrSendRequestFailed, context.DeadlineExceeded), want: true},
+		{name: "gateway unavailable", err: fmt.Errorf("%w: %w: status 503", ErrRequestFailed, ErrServerUnavailable), want: true},
+		{name: "caller cancelled", err: fmt.Errorf("%w: %w", ErrSendRequestFailed, context.Canceled)},
+		{name: "pin mismatch", err: fmt.Errorf("%w: %w", ErrSendRequestFailed, ErrCertificatePinMismatch)},
+		{name: "untrusted certificate", err: fmt.Errorf("%w: %w", ErrSendRequestFailed, certErr)},
+		{name: "other HTTP status", err: fmt.Errorf("%w: status 500", ErrRequestFailed)},
+		{name: "JSON-RPC error", err: &rpcCallError{code: -32000, message: "boom"}},
+		{name: "unknown method", err: fmt.Errorf("%w: sandbox.rename: %w", ErrMethodUnsupported, &rpcCallError{code: rpcCodeMethodNotFound})},
+		{name: "malformed response", err: fmt.Errorf("%w: bad json", ErrUnmarshalRespFailed)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnreachable(tt.err); got != tt.want {
+				t.Errorf("isUnreachable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package msb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SandboxInfo describes a sandbox known to the server.
+type SandboxInfo struct {
+	Name      string           // Sandbox name
+	Namespace string           // Sandbox namespace
+	Image     string           // Image the sandbox was started from
+	MemoryMiB int              // Requested memory in mebibytes
+	CPUs      int              // Requested number of vCPUs
+	IsRunning bool             // Whether the sandbox is currently running
+	Security  *SecurityProfile // Effective security profile, nil if none is applied
+}
+
+// ListSandboxesPage returns a single page of sandboxes in the namespace configured by options.
+// Pass an empty cursor to fetch the first page, then the returned nextCursor to fetch the next;
+// an empty nextCursor means there are no further pages. Cursors are opaque and server-provided.
+// If limit <= 0, the server's default page size is used.
+//
+// Server URL, namespace and API key are taken from options as for NewPythonSandbox.
+// Unless WithHTTPClient is given, calls share one package-level HTTP client, so paging
+// manually reuses connections instead of opening a new pool per page.
+func ListSandboxesPage(ctx context.Context, cursor string, limit int, options ...Option) (page []SandboxInfo, nextCursor string, err error) {
+	b := newListingBase(options)
+	return listSandboxesPage(ctx, b, cursor, limit)
+}
+
+// AllSandboxes returns an iterator over every sandbox in the namespace configured by options,
+// fetching pages of up to limit entries on demand so memory stays bounded for large fleets.
+// Iteration stops after yielding the first error.
+//
+// Example:
+//
+//	for info, err := range msb.AllSandboxes(ctx, 100, msb.WithNamespace("production")) {
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Println(info.Name)
+//	}
+func AllSandboxes(ctx context.Context, limit int, options ...Option) iter.Seq2[SandboxInfo, error] {
+	b := newListingBase(options)
+	return func(yield func(SandboxInfo, error) bool) {
+		cursor := ""
+		for {
+			page, next, err := listSandboxesPage(ctx, b, cursor, limit)
+			if err != nil {
+				yield(SandboxInfo{}, err)
+				return
+			}
+			for _, info := range page {
+				if !yield(info, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// listClients caches the default RPC client used by the listing functions, keyed by the
+// pinned certificate fingerprint ("" when pinning is off).
+var listClients sync.Map
+
+// newListingBase builds a base from options, falling back to a cached client rather than
+// a fresh default one when the caller did not set WithHTTPClient.
+func newListingBase(options []Option) *baseMicroSandbox {
+	return newBaseWithOptions(slices.Concat(options, []Option{withSharedListClient()})...)
+}
+
+func withSharedListClient() Option {
+	return func(msb *baseMicroSandbox) {
+		if msb.rpcClient != nil {
+			return
+		}
+		pin := msb.cfg.certPin
+		if c, ok := listClients.Load(pin); ok {
+			msb.rpcClient = c.(rpcClient)
+			return
+		}
+		c := newDefaultJsonRPCHTTPClient().(*jsonRPCHTTPClient)
+		if pin != "" {
+			pinned, err := pinnedHTTPClient(c.Client, pin)
+			if err != nil {
+				panic(err)
+			}
+			c = &jsonRPCHTTPClient{Client: pinned, pin: pin}
+		}
+		actual, _ := listClients.LoadOrStore(pin, c)
+		msb.rpcClient = actual.(rpcClient)
+	}
+}
+
+func listSandboxesPage(ctx context.Context, b *baseMicroSandbox, cursor string, limit int) ([]SandboxInfo, string, error) {
+	result, err := b.rpcClient.listSandboxes(ctx, &b.cfg, cursor, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrFailedToListSandboxes, err)
+	}
+
+	page := make([]SandboxInfo, 0, len(result.Sandboxes))
+	for _, s := range result.Sandboxes {
+		page = append(page, s.toSandboxInfo())
+	}
+	return page, result.NextCursor, nil
+}
+
+// Listing-related errors
+var (
+	ErrFailedToListSandboxes = errors.New("failed to list sandboxes")
+)
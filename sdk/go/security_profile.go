@@ -0,0 +1,111 @@
+package msb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SecurityProfile hardens the processes inside a sandbox beyond the VM boundary.
+type SecurityProfile struct {
+	// DropCapabilities lists Linux capabilities removed from sandbox processes (e.g. "CAP_NET_RAW").
+	DropCapabilities []string
+	// SeccompPolicy names a seccomp policy known to the server that restricts available syscalls.
+	// Empty leaves the server's default policy in place.
+	SeccompPolicy string
+}
+
+// Wire representation of a security profile, shared by start requests and sandbox listings
+type securityProfileConfig struct {
+	DropCapabilities []string `json:"drop_capabilities,omitempty"`
+	SeccompPolicy    string   `json:"seccomp_policy,omitempty"`
+}
+
+func (p SecurityProfile) toConfig() *securityProfileConfig {
+	return &securityProfileConfig{
+		DropCapabilities: p.DropCapabilities,
+		SeccompPolicy:    p.SeccompPolicy,
+	}
+}
+
+func (c *securityProfileConfig) toSecurityProfile() *SecurityProfile {
+	if c == nil {
+		return nil
+	}
+	return &SecurityProfile{
+		DropCapabilities: c.DropCapabilities,
+		SeccompPolicy:    c.SeccompPolicy,
+	}
+}
+
+// capabilityBits maps Linux capability names, without the CAP_ prefix, to their bit numbers.
+var capabilityBits = map[string]uint{
+	"CHOWN": 0, "DAC_OVERRIDE": 1, "DAC_READ_SEARCH": 2, "FOWNER": 3, "FSETID": 4, "KILL": 5,
+	"SETGID": 6, "SETUID": 7, "SETPCAP": 8, "LINUX_IMMUTABLE": 9, "NET_BIND_SERVICE": 10,
+	"NET_BROADCAST": 11, "NET_ADMIN": 12, "NET_RAW": 13, "IPC_LOCK": 14, "IPC_OWNER": 15,
+	"SYS_MODULE": 16, "SYS_RAWIO": 17, "SYS_CHROOT": 18, "SYS_PTRACE": 19, "SYS_PACCT": 20,
+	"SYS_ADMIN": 21, "SYS_BOOT": 22, "SYS_NICE": 23, "SYS_RESOURCE": 24, "SYS_TIME": 25,
+	"SYS_TTY_CONFIG": 26, "MKNOD": 27, "LEASE": 28, "AUDIT_WRITE": 29, "AUDIT_CONTROL": 30,
+	"SETFCAP": 31, "MAC_OVERRIDE": 32, "MAC_ADMIN": 33, "SYSLOG": 34, "WAKE_ALARM": 35,
+	"BLOCK_SUSPEND": 36, "AUDIT_READ": 37, "PERFMON": 38, "BPF": 39, "CHECKPOINT_RESTORE": 40,
+}
+
+func capabilityBit(name string) (uint, bool) {
+	bit, ok := capabilityBits[strings.TrimPrefix(strings.ToUpper(name), "CAP_")]
+	return bit, ok
+}
+
+// validateSecurityProfile checks that every dropped capability is a known Linux capability,
+// so Start fails before launching the sandbox rather than after.
+func (c *config) validateSecurityProfile() error {
+	if c.security == nil {
+		return nil
+	}
+	for _, name := range c.security.DropCapabilities {
+		if _, ok := capabilityBit(name); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownCapability, name)
+		}
+	}
+	return nil
+}
+
+// verifySecurityProfile inspects a process inside the sandbox and fails with
+// ErrSecurityProfileUnsupported unless the requested profile is in effect. Servers that do not
+// know the security_profile start field ignore it, so the guest is the only reliable signal.
+// Dropped capabilities must be absent from the bounding set; for a seccomp policy, only that some
+// filter is active can be observed, not which one.
+func (s starter) verifySecurityProfile(ctx context.Context) error {
+	profile := s.b.cfg.security
+	if profile == nil {
+		return nil
+	}
+
+	out, err := s.b.runGuestCommand(ctx, "cat", "/proc/self/status")
+	if err != nil {
+		return fmt.Errorf("%w: reading process status: %w", ErrSecurityProfileUnsupported, err)
+	}
+	status := map[string]string{}
+	for line := range strings.Lines(out) {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			status[key] = strings.TrimSpace(value)
+		}
+	}
+
+	if len(profile.DropCapabilities) > 0 {
+		bounding, err := strconv.ParseUint(status["CapBnd"], 16, 64)
+		if err != nil {
+			return fmt.Errorf("%w: unexpected CapBnd %q", ErrSecurityProfileUnsupported, status["CapBnd"])
+		}
+		for _, name := range profile.DropCapabilities {
+			if bit, _ := capabilityBit(name); bounding&(1<<bit) != 0 {
+				return fmt.Errorf("%w: capability %s was not dropped", ErrSecurityProfileUnsupported, name)
+			}
+		}
+	}
+	// Seccomp mode 2 means a filter is installed
+	if profile.SeccompPolicy != "" && status["Seccomp"] != "2" {
+		return fmt.Errorf("%w: no seccomp filter active for policy %q", ErrSecurityProfileUnsupported, profile.SeccompPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package msb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// seedMkdirBatch is the number of directories created per mkdir command while seeding.
+const seedMkdirBatch = 256
+
+// seedChunkSize bounds how much of a seeded file is read (and held in memory) per upload RPC.
+const seedChunkSize = 1 << 20
+
+// seedDir is a local directory uploaded into the sandbox during Start.
+type seedDir struct {
+	local string
+	guest string
+}
+
+// checkSeedDirs verifies that all seed directories exist locally, so Start fails before
+// launching the sandbox rather than after.
+func (c *config) checkSeedDirs() error {
+	for _, sd := range c.seedDirs {
+		info, err := os.Stat(sd.local)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToSeedSandbox, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%w: %s is not a directory", ErrFailedToSeedSandbox, sd.local)
+		}
+	}
+	return nil
+}
+
+// seed uploads every configured seed directory into the freshly started sandbox.
+func (s starter) seed(ctx context.Context) error {
+	for _, sd := range s.b.cfg.seedDirs {
+		if err := s.seedOne(ctx, sd); err != nil {
+			return fmt.Errorf("%w: %s -> %s: %w", ErrFailedToSeedSandbox, sd.local, sd.guest, err)
+		}
+	}
+	return nil
+}
+
+func (s starter) seedOne(ctx context.Context, sd seedDir) error {
+	type file struct {
+		local, guest string
+		perm         fs.FileMode
+	}
+	var dirs []string
+	var files []file
+
+	// WalkDir does not follow a symlinked root, which would otherwise be skipped as non-regular
+	root, err := filepath.EvalSymlinks(sd.local)
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		guest := path.Join(sd.guest, filepath.ToSlash(rel))
+		switch {
+		case d.IsDir():
+			dirs = append(dirs, guest)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files = append(files, file{local: p, guest: guest, perm: info.Mode().Perm()})
+		default:
+			s.b.cfg.logger.Debug("Skipping non-regular file while seeding", "path", p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Directories are created in batches; the walk lists parents before children
+	for batch := range slices.Chunk(dirs, seedMkdirBatch) {
+		if _, err := s.b.runGuestCommand(ctx, "mkdir", append([]string{"-p", "--"}, batch...)...); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, seedChunkSize)
+	for _, f := range files {
+		if err := s.uploadFile(ctx, f.local, f.guest, f.perm, buf); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrFailedToWriteFile, f.guest, err)
+		}
+	}
+
+	s.b.cfg.logger.Debug("Seeded sandbox directory", "sandbox", s.b.cfg.sandboxName(), "local", sd.local, "guest", sd.guest, "files", len(files))
+	return nil
+}
+
+// uploadFile copies the local file to guest in chunks of len(buf), so only one chunk is held in
+// memory at a time. The first write creates or truncates the guest file, even if it is empty.
+func (s starter) uploadFile(ctx context.Context, local, guest string, perm fs.FileMode, buf []byte) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var offset int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		if n > 0 || offset == 0 {
+			if err := s.b.rpcClient.writeFile(ctx, &s.b.cfg, guest, offset, buf[:n], perm); err != nil {
+				return err
+			}
+			offset += int64(n)
+		}
+		if n < len(buf) {
+			return nil
+		}
+	}
+}
+
+// Seeding errors
+var (
+	ErrFailedToSeedSandbox = errors.New("failed to seed sandbox")
+)
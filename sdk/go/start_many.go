@@ -0,0 +1,115 @@
+package msb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// SandboxSpec describes a sandbox to be started by StartMany.
+// Image, MemoryMB and CPUs follow the same defaults as Start.
+type SandboxSpec struct {
+	Language Language // Language of the sandbox (required)
+	Image    string   // Image to start; empty uses the language default
+	MemoryMB int      // Memory in MiB; <= 0 defaults to 512
+	CPUs     int      // Number of vCPUs; <= 0 defaults to 1
+	Options  []Option // Per-sandbox options, applied after the shared options (e.g. WithName)
+}
+
+// StartMany creates and starts a sandbox for each spec, running at most concurrency starts at once
+// (all at once if concurrency <= 0). Results are aligned by index with specs: for each index, either
+// the sandbox is non-nil or the error is.
+//
+// All sandboxes share a single RPC client, and thus its HTTP connection pool, built from options.
+// Transport settings (WithHTTPClient, WithPinnedCertFingerprint) therefore belong in options; a spec
+// whose Options set either fails with ErrSpecOverridesTransport instead of being started.
+//
+// If ctx is cancelled, no further sandboxes are started, and sandboxes already started are stopped
+// on a best-effort basis and reported with the context's error.
+func StartMany(ctx context.Context, specs []SandboxSpec, concurrency int, options ...Option) ([]*langSandbox, []error) {
+	sandboxes := make([]*langSandbox, len(specs))
+	errs := make([]error, len(specs))
+	if len(specs) == 0 {
+		return sandboxes, errs
+	}
+	if concurrency <= 0 || concurrency > len(specs) {
+		concurrency = len(specs)
+	}
+
+	shared := newBaseWithOptions(options...).rpcClient
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+launch:
+	for i, spec := range specs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(specs); j++ {
+				errs[j] = ctx.Err()
+			}
+			break launch
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sandboxes[i], errs[i] = startSpec(ctx, spec, shared, options)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		for i, ls := range sandboxes {
+			if ls == nil {
+				continue
+			}
+			if err := ls.Stop(); err != nil {
+				ls.b.cfg.logger.Error("Failed to stop sandbox after StartMany was cancelled", "sandbox", ls.b.cfg.sandboxName(), "error", err)
+			}
+			sandboxes[i], errs[i] = nil, ctx.Err()
+		}
+	}
+	return sandboxes, errs
+}
+
+func startSpec(ctx context.Context, spec SandboxSpec, shared rpcClient, options []Option) (*langSandbox, error) {
+	lang, err := spec.Language.progLang()
+	if err != nil {
+		return nil, err
+	}
+	if overridesTransport(spec.Options) {
+		return nil, ErrSpecOverridesTransport
+	}
+
+	opts := make([]Option, 0, len(options)+len(spec.Options))
+	opts = append(opts, options...)
+	opts = append(opts, spec.Options...)
+	ls := newLangSandbox(lang, opts...)
+	ls.b.rpcClient = shared
+
+	image := spec.Image
+	if image == "" {
+		image = lang.DefaultImage()
+	}
+	if err := (starter{ls.b}).start(ctx, image, spec.MemoryMB, spec.CPUs); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+// overridesTransport reports whether options set an HTTP client or a certificate pin, which the
+// shared client used by StartMany would otherwise silently replace.
+func overridesTransport(options []Option) bool {
+	probe := &baseMicroSandbox{}
+	for _, opt := range options {
+		opt(probe)
+	}
+	return probe.rpcClient != nil || probe.cfg.certPin != ""
+}
+
+// StartMany errors
+var (
+	ErrSpecOverridesTransport = errors.New("sandbox spec options must not set an HTTP client or certificate pin")
+)
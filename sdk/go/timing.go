@@ -0,0 +1,36 @@
+package msb
+
+import "time"
+
+// Timing splits the duration of an execution into the phases reported by the server.
+type Timing struct {
+	Queue    time.Duration // Time spent waiting for a free kernel or process slot
+	Exec     time.Duration // Time spent executing in the kernel or process
+	Transfer time.Duration // Time spent collecting and shipping output back
+}
+
+// Total returns the sum of all phases.
+func (t Timing) Total() time.Duration {
+	return t.Queue + t.Exec + t.Transfer
+}
+
+// Internal structure for parsing timing metadata, in fractional milliseconds
+type timingData struct {
+	QueueMs    float64 `json:"queue_ms"`
+	ExecMs     float64 `json:"exec_ms"`
+	TransferMs float64 `json:"transfer_ms"`
+}
+
+func (td *timingData) toTiming() (Timing, bool) {
+	if td == nil {
+		return Timing{}, false
+	}
+	ms := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Millisecond))
+	}
+	return Timing{
+		Queue:    ms(td.QueueMs),
+		Exec:     ms(td.ExecMs),
+		Transfer: ms(td.TransferMs),
+	}, true
+}